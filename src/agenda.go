@@ -0,0 +1,211 @@
+package todo
+
+// Implementation of the time-oriented views of a journal: the agenda (the
+// list of the tasks day by day) and the calendar (a month grid). The tasks are
+// laid out at their dates: the creation date, the due date, and the dates
+// when they have been started and done (from the history of their status).
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// eventKind identifies a date of a task
+type eventKind int
+
+// Enumeration of the kinds of dates of a task
+const (
+	eventCreated eventKind = iota
+	eventStarted
+	eventDone
+	eventDue
+)
+
+var eventKinds = []eventKind{eventCreated, eventStarted, eventDone, eventDue}
+
+var eventLabels = map[eventKind]string{
+	eventCreated: "created",
+	eventStarted: "started",
+	eventDone:    "done",
+	eventDue:     "due",
+}
+
+var eventSymbols = map[eventKind]string{
+	eventCreated: "+",
+	eventStarted: ">",
+	eventDone:    "x",
+	eventDue:     "!",
+}
+
+const (
+	calendarCellWidth = 10
+	calendarToday     = "*"
+)
+
+// taskEvent is a date of a task
+type taskEvent struct {
+	kind eventKind
+	task Task
+}
+
+// dayEvents returns the dates of the tasks of this journal, indexed by day
+// (ISO label of the date), in the chronological order within a day.
+func (journal TaskJournal) dayEvents() map[string][]taskEvent {
+	type datedEvent struct {
+		taskEvent
+		date int64
+	}
+	dated := make([]datedEvent, 0)
+	for _, task := range journal.TaskList {
+		dates := map[eventKind]int64{
+			eventCreated: task.Timestamp,
+			eventStarted: task.StatusDate(StatusDoing),
+			eventDone:    task.CompletionDate(),
+			eventDue:     task.Due,
+		}
+		for _, kind := range eventKinds {
+			if dates[kind] != 0 {
+				dated = append(dated, datedEvent{taskEvent{kind, task}, dates[kind]})
+			}
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool { return dated[i].date < dated[j].date })
+
+	events := make(map[string][]taskEvent)
+	for _, event := range dated {
+		day := time.Unix(event.date, 0).Format(layoutISO)
+		events[day] = append(events[day], event.taskEvent)
+	}
+	return events
+}
+
+// Agenda returns the agenda of the tasks of this journal over ndays days from
+// the date first: the dates of the tasks day by day (the days with no date
+// are skipped). The agenda starts with the overdue tasks, i.e. the tasks that
+// are not done and whose due date is before today.
+func (journal TaskJournal) Agenda(first time.Time, ndays int, today time.Time) string {
+	s := fmt.Sprintln()
+	overdue := ""
+	for _, task := range journal.TaskList {
+		if task.Status != StatusDone && task.Due != 0 && task.Due < dayStart(today).Unix() {
+			overdue += fmt.Sprintf("  %-7s %s\n", time.Unix(task.Due, 0).Format(layoutISO), task.String())
+		}
+	}
+	if overdue != "" {
+		s += "Overdue:\n" + overdue + "\n"
+	}
+
+	events := journal.dayEvents()
+	nevents := 0
+	for day := 0; day < ndays; day++ {
+		date := dayStart(first).AddDate(0, 0, day)
+		key := date.Format(layoutISO)
+		if len(events[key]) == 0 {
+			continue
+		}
+		header := date.Format("Mon " + layoutISO)
+		if key == today.Format(layoutISO) {
+			header += " (today)"
+		}
+		s += header + "\n"
+		for _, event := range events[key] {
+			s += fmt.Sprintf("  %-7s %s\n", eventLabels[event.kind], event.task.String())
+		}
+		s += fmt.Sprintln()
+		nevents += len(events[key])
+	}
+	if nevents == 0 && overdue == "" {
+		s += fmt.Sprintf("%s\n\n", notasks)
+	} else {
+		s += fmt.Sprintf("%s\n", legendString())
+	}
+	return s
+}
+
+// calendarCell returns the lines of the cell of the date in the calendar: the
+// day of the month, the number of dates of each kind, and the UIDs of the
+// tasks.
+func calendarCell(date time.Time, events []taskEvent, today time.Time) []string {
+	day := fmt.Sprint(date.Day())
+	if date.Format(layoutISO) == today.Format(layoutISO) {
+		day += " " + calendarToday
+	}
+	lines := []string{day}
+	if len(events) == 0 {
+		return lines
+	}
+
+	counts := make(map[eventKind]int)
+	uids := make([]string, 0)
+	listed := make(map[TaskID]bool)
+	for _, event := range events {
+		counts[event.kind]++
+		if !listed[event.task.UIndex] {
+			listed[event.task.UIndex] = true
+			uids = append(uids, fmt.Sprint(event.task.UIndex))
+		}
+	}
+	summary := make([]string, 0)
+	for _, kind := range eventKinds {
+		if counts[kind] > 0 {
+			summary = append(summary, fmt.Sprintf("%s%d", eventSymbols[kind], counts[kind]))
+		}
+	}
+	lines = append(lines, wrapWords(strings.Join(summary, " "), calendarCellWidth-1)...)
+	lines = append(lines, wrapWords(strings.Join(uids, ", "), calendarCellWidth-1)...)
+	return lines
+}
+
+// Calendar returns the calendar of the month of the given date, as a grid
+// whose cells (one per day) give the number of dates of the tasks of this
+// journal and the UIDs of these tasks.
+func (journal TaskJournal) Calendar(month time.Time, today time.Time) string {
+	events := journal.dayEvents()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	border := "+" + strings.Repeat(strings.Repeat("-", calendarCellWidth)+"+", 7) + "\n"
+
+	s := fmt.Sprintln()
+	title := first.Format("January 2006")
+	s += strings.Repeat(" ", (len(border)-len(title))/2) + title + "\n"
+	s += border
+	line := "|"
+	for day := 0; day < 7; day++ {
+		line += padRight(" "+weekStart(first.Unix(), 0).AddDate(0, 0, day).Format("Mon"), calendarCellWidth) + "|"
+	}
+	s += line + "\n" + border
+
+	for week := weekStart(first.Unix(), 0); week.Before(first.AddDate(0, 1, 0)); week = week.AddDate(0, 0, 7) {
+		cells := make([][]string, 7)
+		nlines := 0
+		for day := 0; day < 7; day++ {
+			date := week.AddDate(0, 0, day)
+			if date.Month() == first.Month() {
+				cells[day] = calendarCell(date, events[date.Format(layoutISO)], today)
+			}
+			if len(cells[day]) > nlines {
+				nlines = len(cells[day])
+			}
+		}
+		for i := 0; i < nlines; i++ {
+			line := "|"
+			for _, cell := range cells {
+				text := ""
+				if i < len(cell) {
+					text = " " + cell[i]
+				}
+				line += padRight(text, calendarCellWidth) + "|"
+			}
+			s += line + "\n"
+		}
+		s += border
+	}
+
+	legend := make([]string, 0)
+	for _, kind := range eventKinds {
+		legend = append(legend, fmt.Sprintf("%s %s", eventSymbols[kind], eventLabels[kind]))
+	}
+	s += fmt.Sprintf("Legend: %s  %s today\n", strings.Join(legend, "  "), calendarToday)
+	return s
+}
@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// createAgendaJournal creates a journal whose tasks have dates around today
+func createAgendaJournal(today time.Time) TaskJournal {
+	day := func(offset int) int64 { return dayStart(today).AddDate(0, 0, offset).Add(10 * time.Hour).Unix() }
+	journal := CreateTestJournal()
+	for i := range journal.TaskList {
+		journal.TaskList[i].Timestamp = day(-10)
+		journal.TaskList[i].History = nil
+	}
+	journal.TaskList[0].Due = day(-2) // overdue
+	journal.TaskList[1].Due = day(3)
+	journal.TaskList[2].Status = StatusDone
+	journal.TaskList[2].History = StatusHistory{{Status: StatusDoing, Timestamp: day(-1)}, {Status: StatusDone, Timestamp: day(0)}}
+	journal.TaskList[3].Due = day(30)
+	return journal
+}
+
+func TestAgenda(t *testing.T) {
+	today := time.Date(2024, 3, 14, 15, 0, 0, 0, time.Local)
+	journal := createAgendaJournal(today)
+
+	agenda := journal.Agenda(today, 14, today)
+	printlog(agenda)
+
+	if !strings.Contains(agenda, "Overdue:\n  2024-03-12") {
+		t.Error("The task 1 should be overdue")
+	}
+	if !strings.Contains(agenda, "Thu 2024-03-14 (today)\n  done") {
+		t.Error("The task 3 should be done today")
+	}
+	if !strings.Contains(agenda, "Sun 2024-03-17\n  due") {
+		t.Error("The task 2 should be due on 2024-03-17")
+	}
+	// The due date of the task 4 and the dates before today are out of the agenda
+	if strings.Contains(agenda, "2024-04-13") || strings.Contains(agenda, "2024-03-13") {
+		t.Error("The agenda contains dates out of the window")
+	}
+
+	week := journal.Agenda(weekStart(today.Unix(), 0), 7, today)
+	if !strings.Contains(week, "Wed 2024-03-13\n  started") {
+		t.Error("The task 3 should be started on 2024-03-13")
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	today := time.Date(2024, 3, 14, 15, 0, 0, 0, time.Local)
+	journal := createAgendaJournal(today)
+
+	calendar := journal.Calendar(today, today)
+	printlog(calendar)
+
+	if !strings.Contains(calendar, "March 2024") {
+		t.Error("The title is missing")
+	}
+	// March 2024 spans 5 weeks: one border for the header and one for each week
+	border := "+" + strings.Repeat(strings.Repeat("-", calendarCellWidth)+"+", 7)
+	if strings.Count(calendar, border) != 7 {
+		t.Errorf("Nb borders is %d (should be %d)", strings.Count(calendar, border), 7)
+	}
+	if !strings.Contains(calendar, "| 14 *     |") {
+		t.Error("Today should be marked")
+	}
+	// The 4 tasks have been created on 2024-03-04
+	lines := strings.Split(calendar, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "| 4        | 5        |") {
+			if !strings.Contains(lines[i+1], "| +4       |") || !strings.Contains(lines[i+2], "| 1, 2, 3, |") {
+				t.Errorf("The cell of 2024-03-04 is not valid:\n%s", strings.Join(lines[i:i+4], "\n"))
+			}
+		}
+	}
+}
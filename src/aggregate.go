@@ -0,0 +1,142 @@
+package todo
+
+// Implementation of the aggregated views of several contexts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContextJournal associates a task journal to the context it belongs to
+type ContextJournal struct {
+	Context Context
+	Journal TaskJournal
+}
+
+// label returns the address of the given task in the aggregated views, i.e.
+// a string of the form context:uid.
+func (contextJournal ContextJournal) label(task Task) string {
+	return fmt.Sprintf("%s:%d", contextJournal.Context.Name, task.UIndex)
+}
+
+// ContextJournalArray is a list of ContextJournal, used to create aggregated
+// views of the tasks of several contexts.
+type ContextJournalArray []ContextJournal
+
+// LoadJournals loads the journals of the contexts whose names are given in
+// argument (all the contexts of the configuration if names is empty). The
+// resulting list is sorted by context name.
+func (config *Config) LoadJournals(names []string) (ContextJournalArray, error) {
+	if len(names) == 0 {
+		for _, context := range config.ContextList {
+			names = append(names, context.Name)
+		}
+	}
+	journals := make(ContextJournalArray, 0, len(names))
+	for _, name := range names {
+		context := config.GetContext(name)
+		if context == nil {
+			return nil, fmt.Errorf("ERR: The context %s does not exists", name)
+		}
+		var journal TaskJournal
+		err := journal.LoadOrCreate(context.JournalPath())
+		if err != nil {
+			return nil, err
+		}
+		journals = append(journals, ContextJournal{Context: *context, Journal: journal})
+	}
+	sort.Slice(journals, func(i, j int) bool {
+		return journals[i].Context.Name < journals[j].Context.Name
+	})
+	return journals, nil
+}
+
+// taskStringFunction returns the function that creates the string
+// representation of a task of the given context journal. All the task labels
+// are aligned on the longest context name of this list.
+func (journals ContextJournalArray) taskStringFunction(contextJournal ContextJournal) func(Task) string {
+	width := 0
+	for _, cj := range journals {
+		for _, task := range cj.Journal.TaskList {
+			if size := len(cj.label(task)); size > width {
+				width = size
+			}
+		}
+	}
+	return func(task Task) string {
+		return task.onelineString(fmt.Sprintf("%*s", width, contextJournal.label(task)))
+	}
+}
+
+// groupString returns the header of the group of tasks of the given context
+func groupString(context Context) string {
+	header := fmt.Sprintf("%s:", context.Name)
+	underline := ""
+	for range header {
+		underline += "-"
+	}
+	return fmt.Sprintf("%s\n%s\n", header, underline)
+}
+
+// ListWithFilter returns a string representation of the tasks of all the
+// journals that satisfy the given filter. The tasks are grouped by context
+// and addressed as context:uid.
+func (journals ContextJournalArray) ListWithFilter(taskFilter TaskFilter) string {
+	s := fmt.Sprintln()
+	ntotal := 0
+	for _, contextJournal := range journals {
+		taskString := journals.taskStringFunction(contextJournal)
+		listing, nlisted := contextJournal.Journal.listTasks(taskFilter, taskString)
+		if nlisted == 0 {
+			continue
+		}
+		s += groupString(contextJournal.Context)
+		s += listing
+		s += fmt.Sprintln()
+		ntotal += nlisted
+	}
+	if ntotal == 0 {
+		s += fmt.Sprintf("%s\n\n", notasks)
+	} else {
+		s += fmt.Sprintf("%s\n", legendString())
+	}
+	return s
+}
+
+// List returns a string representation of all the tasks of all the journals
+func (journals ContextJournalArray) List() string {
+	return journals.ListWithFilter(TaskFilterAll)
+}
+
+// Tree returns a string representation of the tree structure of the tasks of
+// all the journals, grouped by context.
+func (journals ContextJournalArray) Tree() string {
+	return journals.TreeWithOptions(TreeOptions{})
+}
+
+// TreeWithOptions returns a string representation of the tree structure of the
+// tasks of all the journals, grouped by context, and restricted by the options
+// (the root task of the options is ignored, its UID being ambiguous).
+func (journals ContextJournalArray) TreeWithOptions(options TreeOptions) string {
+	options.Root = NoUID
+	s := fmt.Sprintln()
+	ntotal := 0
+	for _, contextJournal := range journals {
+		taskString := journals.taskStringFunction(contextJournal)
+		tree := treeString(contextJournal.Journal.TaskList, taskString, options)
+		if tree == "" {
+			continue
+		}
+		s += groupString(contextJournal.Context)
+		s += strings.TrimPrefix(tree, "\n")
+		s += fmt.Sprintln()
+		ntotal += len(contextJournal.Journal.TaskList)
+	}
+	if ntotal == 0 {
+		s += fmt.Sprintf("%s\n\n", notasks)
+	} else {
+		s += fmt.Sprintf("%s\n", legendString())
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// commandAgenda is the arguments parser of the command agenda
+func commandAgenda(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var today bool
+	flagset.BoolVar(&today, "today", false, "Print the agenda of today")
+	var week bool
+	flagset.BoolVar(&week, "week", false, "Print the agenda of this week")
+	var days int
+	flagset.IntVar(&days, "d", 14, "Print the agenda of the specified number of days from today")
+
+	flagset.Parse(args)
+
+	if days < 1 {
+		return errors.New("ERR: the number of days should be at least 1")
+	}
+
+	now := time.Now()
+	first := now
+	if today {
+		days = 1
+	} else if week {
+		// The week begins on monday
+		first = now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+		days = 7
+	}
+
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	fmt.Println(journal.Agenda(first, days, now))
+	return nil
+}
+
+// commandCalendar is the arguments parser of the command calendar
+func commandCalendar(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+	flagset.Usage = func() {
+		fmt.Fprintf(flagset.Output(), "Usage of %s: %s [YYYY-MM]\n", cmdname, cmdname)
+		fmt.Fprintf(flagset.Output(), "Print the calendar of the specified month (default to the current month)\n")
+		flagset.PrintDefaults()
+	}
+
+	flagset.Parse(args)
+
+	now := time.Now()
+	month := now
+	if flagset.NArg() > 0 {
+		var err error
+		month, err = time.ParseInLocation("2006-01", flagset.Arg(0), time.Local)
+		if err != nil {
+			return fmt.Errorf("ERR: the month %s is not valid (should be YYYY-MM)", flagset.Arg(0))
+		}
+	}
+
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	fmt.Println(journal.Calendar(month, now))
+	return nil
+}
@@ -17,6 +17,7 @@ func commandArchive(cmdname string, args []string) error {
 	flagset.Var(&add, "a", "Archive the specified tasks (comma separated list of indeces)")
 	var restore todo.TaskIDArray
 	flagset.Var(&restore, "r", "Restore the specified tasks (comma separated list of indeces)")
+	recursive := recursiveFlag(flagset)
 
 	flagset.Parse(args)
 
@@ -24,10 +25,10 @@ func commandArchive(cmdname string, args []string) error {
 		return listArchive()
 	}
 	if len(add) > 0 {
-		return moveToArchive(add)
+		return moveToArchive(add, *recursive)
 	}
 	if len(restore) > 0 {
-		return restoreFromArchive(restore)
+		return restoreFromArchive(restore, *recursive)
 	}
 
 	return listArchive()
@@ -42,7 +43,18 @@ func listArchive() error {
 	return nil
 }
 
-func moveToArchive(indeces todo.TaskIDArray) error {
+// archiveUID returns the UID of a task moved to the archive: its global index,
+// that is unique in the context (see todo fsck for the journals created by the
+// former versions of todo). It is not an identity of the task (see the GUID).
+func archiveUID(task todo.Task) todo.TaskID {
+	return task.GIndex
+}
+
+// moveToArchive moves the tasks (and their descendants if recursive) from the
+// journal to the archive. The parent relations between the archived tasks are
+// kept, and the relations with the tasks left in the journal are restored when
+// the tasks are restored (see TaskJournal.Move).
+func moveToArchive(indeces todo.TaskIDArray, recursive bool) error {
 	archive, err := getActiveArchive()
 	if err != nil {
 		return err
@@ -53,20 +65,27 @@ func moveToArchive(indeces todo.TaskIDArray) error {
 		return err
 	}
 
+	indeces, err = withDescendants(journal, indeces, recursive)
+	if err != nil {
+		return err
+	}
 	for _, index := range indeces {
-		task, err := journal.Delete(index)
+		task, err := journal.GetTask(index)
 		if err != nil {
-			fmt.Println(err)
-		} else {
-			task.UIndex = task.GIndex
-			err = archive.Add(task)
-			if err != nil {
-				fmt.Println(err)
-			} else {
-				fmt.Printf("Task %d moved to the archive with a new usage index: %d\n", index, task.UIndex)
-			}
+			return err
+		}
+		if _, err := archive.GetTask(archiveUID(*task)); err == nil {
+			return fmt.Errorf("ERR: The global index %d of the task %d is used in the archive (use todo fsck to migrate the global indeces)", task.GIndex, index)
 		}
 	}
+	uids, err := journal.Move(archive, indeces, archiveUID)
+	if err != nil {
+		return err
+	}
+	for _, index := range indeces {
+		fmt.Printf("Task %d moved to the archive with a new usage index: %d\n", index, uids[index])
+	}
+	updateParentStatus(journal)
 	err = archive.Save()
 	if err != nil {
 		return err
@@ -74,7 +93,10 @@ func moveToArchive(indeces todo.TaskIDArray) error {
 	return journal.Save()
 }
 
-func restoreFromArchive(indeces todo.TaskIDArray) error {
+// restoreFromArchive moves the tasks (and their descendants if recursive) from
+// the archive to the journal. The parent relations between the restored tasks
+// are kept, and the relations they had before being archived are restored.
+func restoreFromArchive(indeces todo.TaskIDArray, recursive bool) error {
 	archive, err := getActiveArchive()
 	if err != nil {
 		return err
@@ -85,19 +107,19 @@ func restoreFromArchive(indeces todo.TaskIDArray) error {
 		return err
 	}
 
+	indeces, err = withDescendants(archive, indeces, recursive)
+	if err != nil {
+		return err
+	}
+	freeUID := func(todo.Task) todo.TaskID { return journal.GetFreeUID() }
+	uids, err := archive.Move(journal, indeces, freeUID)
+	if err != nil {
+		return err
+	}
+	// The tasks archived by the former versions of todo have no short ID
+	journal.AssignShortIDs()
 	for _, index := range indeces {
-		task, err := archive.Delete(index)
-		if err != nil {
-			fmt.Println(err)
-		} else {
-			task.UIndex = journal.GetFreeUID()
-			err = journal.Add(task)
-			if err != nil {
-				fmt.Println(err)
-			} else {
-				fmt.Printf("Task %d restored from archive with a new usage index: %d\n", index, task.UIndex)
-			}
-		}
+		fmt.Printf("Task %d restored from archive with a new usage index: %d\n", index, uids[index])
 	}
 	err = archive.Save()
 	if err != nil {
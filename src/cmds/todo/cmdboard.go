@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"strconv"
 
 	"galuma.net/todo"
 )
@@ -19,9 +21,27 @@ func commandBoard(cmdname string, args []string) error {
 	flagset.Var(&add, "a", "Add on board the specified tasks (comma separeted list of indeces)")
 	var remove todo.TaskIDArray
 	flagset.Var(&remove, "r", "Remove from board the specified tasks (comma separeted list of indeces)")
+	recursive := recursiveFlag(flagset)
+	var kanban bool
+	flagset.BoolVar(&kanban, "k", false, "List the tasks on board as a kanban (one column per status)")
+	var width int
+	flagset.IntVar(&width, "w", 0, "Width of the kanban view (default to $COLUMNS, or to the width of the terminal)")
+	var allContexts bool
+	flagset.BoolVar(&allContexts, "all-contexts", false, "List the tasks on board of all the contexts")
+	var contexts string
+	flagset.StringVar(&contexts, "contexts", "", "List the tasks on board of the specified contexts (comma separated list of names)")
 
 	flagset.Parse(args)
 
+	if kanban {
+		if width <= 0 {
+			width = terminalWidth()
+		}
+		return listKanban(allContexts || contexts != "", contexts, width)
+	}
+	if allContexts || contexts != "" {
+		return listContextsBoard(contexts)
+	}
 	if list {
 		return listBoard()
 	}
@@ -31,10 +51,10 @@ func commandBoard(cmdname string, args []string) error {
 
 	// At this point the list of indeces is specified
 	if len(add) > 0 {
-		return addOnBoard(add)
+		return addOnBoard(add, *recursive)
 	}
 	if len(remove) > 0 {
-		return removeFromBoard(remove)
+		return removeFromBoard(remove, *recursive)
 	}
 
 	return listBoard()
@@ -50,6 +70,46 @@ func listBoard() error {
 	return nil
 }
 
+func listContextsBoard(contexts string) error {
+	journals, err := getContextJournals(contexts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(journals.ListWithFilter(todo.TaskFilterOnBoard))
+	return nil
+}
+
+// terminalWidth returns the width of the terminal, as given by the variable
+// COLUMNS or queried from the terminal, or a default width if the output is
+// not a terminal.
+func terminalWidth() int {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && width > 0 {
+		return width
+	}
+	if width = queryTerminalWidth(); width > 0 {
+		return width
+	}
+	return todo.KanbanDefaultWidth
+}
+
+func listKanban(severalContexts bool, contexts string, width int) error {
+	if severalContexts {
+		journals, err := getContextJournals(contexts)
+		if err != nil {
+			return err
+		}
+		fmt.Println(journals.Kanban(todo.TaskFilterOnBoard, width))
+		return nil
+	}
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	fmt.Println(journal.Kanban(todo.TaskFilterOnBoard, width))
+	return nil
+}
+
 func clearBoard() error {
 	journal, err := getActiveJournal()
 	if err != nil {
@@ -69,11 +129,15 @@ func clearBoard() error {
 	return nil
 }
 
-func addOnBoard(indeces todo.TaskIDArray) error {
+func addOnBoard(indeces todo.TaskIDArray, recursive bool) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
+	indeces, err = withDescendants(journal, indeces, recursive)
+	if err != nil {
+		return err
+	}
 	for _, uindex := range indeces {
 		err := journal.AddOnBoard(uindex)
 		if err != nil {
@@ -85,11 +149,15 @@ func addOnBoard(indeces todo.TaskIDArray) error {
 	return journal.Save()
 }
 
-func removeFromBoard(indeces todo.TaskIDArray) error {
+func removeFromBoard(indeces todo.TaskIDArray, recursive bool) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
+	indeces, err = withDescendants(journal, indeces, recursive)
+	if err != nil {
+		return err
+	}
 	for _, uindex := range indeces {
 		err := journal.RemoveFromBoard(uindex)
 		if err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 
@@ -15,34 +16,60 @@ func commandChild(cmdname string, args []string) error {
 	flagset.Var(&children, "c", "List of children tasks (comma separated list of indeces)")
 	var parent todo.TaskID
 	flagset.Var(&parent, "p", "Index of the parent task")
+	var unparent bool
+	flagset.BoolVar(&unparent, "unparent", false, "Make root tasks of the children tasks (remove their parent)")
+	var subtree bool
+	flagset.BoolVar(&subtree, "m", false, "Move the children tasks with their descendants (default)")
+	var detach bool
+	flagset.BoolVar(&detach, "d", false, "Move the children tasks without their descendants (their own children are given their former parent)")
 
 	flagset.Parse(args)
 
-	if len(children) > 0 {
-		return addChildren(parent, children)
+	if len(children) == 0 {
+		flagset.Usage()
+		return errors.New("ERR: The children tasks should be specified (-c)")
 	}
-
-	return nil
+	if unparent && parent != todo.NoUID {
+		return errors.New("ERR: The options -p and -unparent are exclusive")
+	}
+	if subtree && detach {
+		return errors.New("ERR: The options -m and -d are exclusive")
+	}
+	if !unparent && parent == todo.NoUID {
+		flagset.Usage()
+		return errors.New("ERR: The parent task should be specified (-p), or use -unparent")
+	}
+	return addChildren(parent, children, !detach)
 }
 
-func addChildren(parentUID todo.TaskID, children todo.TaskIDArray) error {
+// addChildren makes the tasks children of the task parentUID (or root tasks if
+// parentUID is NoUID). If subtree is true, the children are moved with their
+// descendants, else their own children are given their former parent.
+func addChildren(parentUID todo.TaskID, children todo.TaskIDArray, subtree bool) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
-
-	parent, err := journal.GetTask(parentUID)
+	parentUID, err = journal.Resolve(parentUID)
+	if err != nil {
+		return err
+	}
+	children, err = journal.ResolveAll(children)
 	if err != nil {
 		return err
 	}
 
 	for _, index := range children {
-		child, err := journal.GetTask(index)
+		err := journal.SetParent(index, parentUID, subtree)
 		if err != nil {
 			fmt.Println(err)
+		} else if parentUID == todo.NoUID {
+			fmt.Printf("Task %d is now a root task\n", index)
+		} else {
+			fmt.Printf("Task %d is now a child of the task %d\n", index, parentUID)
 		}
-		child.ParentID = parent.UIndex
 	}
 
+	updateParentStatus(journal)
 	return journal.Save()
 }
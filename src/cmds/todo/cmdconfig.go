@@ -4,6 +4,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"strings"
 
 	"galuma.net/todo"
 )
@@ -37,8 +38,29 @@ func commandConfig(cmdname string, args []string) error {
 	help = "Print all information concerning the configuration"
 	flagset.BoolVar(&info, "i", false, help)
 
+	flagset.Usage = func() {
+		fmt.Fprintf(flagset.Output(), "Usage of %s:\n", cmdname)
+		flagset.PrintDefaults()
+		fmt.Fprintf(flagset.Output(), "\nEdition of the configuration parameters:\n")
+		fmt.Fprintf(flagset.Output(), "  %s get [<key>]       print the value of the parameter (all if no key)\n", cmdname)
+		fmt.Fprintf(flagset.Output(), "  %s set <key> <value> set the value of the parameter\n", cmdname)
+		fmt.Fprintf(flagset.Output(), "  %s unset <key>       reset the parameter to its default value\n", cmdname)
+		fmt.Fprintf(flagset.Output(), "\nWith <key> in: %s\n", todo.ParameterNames())
+	}
+
 	flagset.Parse(args)
 
+	if flagset.NArg() > 0 {
+		switch flagset.Arg(0) {
+		case "get":
+			return getParameter(flagset.Args()[1:])
+		case "set":
+			return setParameter(flagset.Args()[1:])
+		case "unset":
+			return unsetParameter(flagset.Args()[1:])
+		}
+	}
+
 	if newName != "" {
 		if path == "" {
 			path = todo.DefaultContextPath(newName)
@@ -149,3 +171,79 @@ func removeContext(name string) error {
 	}
 	return err
 }
+
+func getParameter(args []string) error {
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		fmt.Println(config.Parameters.String())
+		return nil
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("ERR: the arguments %v are not valid (usage: get [<key>])", args)
+	}
+	value, err := config.Parameters.Get(args[0])
+	if err == nil {
+		fmt.Println(value)
+	}
+	return err
+}
+
+func setParameter(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("ERR: the arguments %v are not valid (usage: set <key> <value>)", args)
+	}
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(args[0], "DefaultCommand") {
+		err = checkDefaultCommand(args[1])
+		if err != nil {
+			return err
+		}
+	}
+	err = config.Parameters.Set(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	err = config.Save()
+	if err == nil {
+		fmt.Println(config.Parameters.String())
+	}
+	return err
+}
+
+func unsetParameter(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("ERR: the arguments %v are not valid (usage: unset <key>)", args)
+	}
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	err = config.Parameters.Unset(args[0])
+	if err != nil {
+		return err
+	}
+	err = config.Save()
+	if err == nil {
+		fmt.Println(config.Parameters.String())
+	}
+	return err
+}
+
+// checkDefaultCommand returns an error if the given command line does not
+// start with the name of a todo command.
+func checkDefaultCommand(cmdline string) error {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 {
+		return errors.New("ERR: the default command can not be blank")
+	}
+	if contains(commandNames, fields[0]) {
+		return nil
+	}
+	return fmt.Errorf("ERR: the default command %s is not a todo command (should be in: %s)", fields[0], commandNames)
+}
@@ -17,25 +17,31 @@ func commandDelete(cmdname string, args []string) error {
 
 	var archive todo.TaskIDArray
 	flagset.Var(&archive, "a", "Move to the archive the specified tasks (comma separated list of indeces)")
+	recursive := recursiveFlag(flagset)
 
 	flagset.Parse(args)
 
 	if len(delete) > 0 {
-		return deleteFromJournal(delete)
+		return deleteFromJournal(delete, *recursive)
 	}
 	if len(archive) > 0 {
-		return moveToArchive(archive)
+		return moveToArchive(archive, *recursive)
 	}
 
 	flagset.Usage()
 	return errors.New("ERR: At least one option should be specified (-d or -a)")
 }
 
-func deleteFromJournal(indeces todo.TaskIDArray) error {
+// deleteFromJournal deletes the tasks (and their descendants if recursive)
+func deleteFromJournal(indeces todo.TaskIDArray, recursive bool) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
+	indeces, err = withDescendants(journal, indeces, recursive)
+	if err != nil {
+		return err
+	}
 	for _, index := range indeces {
 		_, err := journal.Delete(index)
 		if err != nil {
@@ -44,5 +50,6 @@ func deleteFromJournal(indeces todo.TaskIDArray) error {
 			fmt.Printf("Task of index %d has been deleted\n", index)
 		}
 	}
+	updateParentStatus(journal)
 	return journal.Save()
 }
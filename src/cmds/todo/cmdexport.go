@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"galuma.net/todo"
+)
+
+// commandExport is the arguments parser of the command export
+func commandExport(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var format string
+	help := fmt.Sprintf("Format of the export, in: %s (default from the file extension)", formatNames())
+	flagset.StringVar(&format, "format", "", help)
+	var outpath string
+	flagset.StringVar(&outpath, "f", "", "Write the export in the specified file (default to the standard output)")
+	var board bool
+	flagset.BoolVar(&board, "b", false, "Export only the tasks on board")
+	var status string
+	flagset.StringVar(&status, "s", "", "Export only the tasks with the specified status (comma separated list of labels)")
+	var root todo.TaskID
+	flagset.Var(&root, "root", "Export only the specified task and its descendants (subtree)")
+	var notes bool
+	flagset.BoolVar(&notes, "notes", false, "Export also the contents of the notes (if supported by the format)")
+	var columns string
+	help = fmt.Sprintf("Columns of the csv export (comma separated list in: %s)", todo.CSVColumnNames())
+	flagset.StringVar(&columns, "columns", "", help)
+	var allContexts bool
+	flagset.BoolVar(&allContexts, "all-contexts", false, "Export all the contexts as a static html site in the directory specified by -f")
+	var contexts string
+	flagset.StringVar(&contexts, "contexts", "", "Export the specified contexts (comma separated list of names) as a static html site in the directory specified by -f")
+
+	flagset.Parse(args)
+
+	if allContexts || contexts != "" {
+		if format != "" && format != "html" {
+			return errors.New("ERR: the export of several contexts is available only for the html format")
+		}
+		if outpath == "" {
+			flagset.Usage()
+			return errors.New("ERR: the directory of the static site should be specified (-f)")
+		}
+		return exportSite(outpath, contexts)
+	}
+
+	exchangeFormat, err := getFormat(format, outpath)
+	if err != nil {
+		return err
+	}
+
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+
+	filter, err := taskFilter(board, status)
+	if err != nil {
+		return err
+	}
+	if root != todo.NoUID {
+		root, err = journal.Resolve(root)
+		if err != nil {
+			return err
+		}
+		subtreeFilter, err := journal.SubtreeFilter(root)
+		if err != nil {
+			return err
+		}
+		statusFilter := filter
+		filter = func(task todo.Task) bool { return subtreeFilter(task) && statusFilter(task) }
+	}
+	options := exportOptions{
+		contextName: config.GetActiveContext().Name,
+		filter:      filter,
+		notes:       notes,
+	}
+	if columns != "" {
+		options.columns = strings.Split(columns, ",")
+	}
+
+	text, err := exchangeFormat.Export(journal, options)
+	if err != nil {
+		return err
+	}
+	if outpath == "" {
+		fmt.Print(text)
+		return nil
+	}
+	err = todo.WriteBytes(outpath, []byte(text))
+	if err == nil {
+		fmt.Printf("The tasks have been exported in the %s file: %s\n", exchangeFormat.Name, outpath)
+	}
+	return err
+}
+
+// exportSite writes the static html site of the contexts in the directory
+// dirpath (all the contexts if contexts is blank).
+func exportSite(dirpath string, contexts string) error {
+	journals, err := getContextJournals(contexts)
+	if err != nil {
+		return err
+	}
+	pages := journals.HTMLSite("TODO lists")
+	filenames := make([]string, 0, len(pages))
+	for filename := range pages {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	for _, filename := range filenames {
+		err = todo.WriteBytes(filepath.Join(dirpath, filename), []byte(pages[filename]))
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("The static site has been exported in the directory: %s (%d pages)\n", dirpath, len(pages))
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"galuma.net/todo"
+)
+
+// commandFsck is the arguments parser of the command fsck
+func commandFsck(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var dryrun bool
+	flagset.BoolVar(&dryrun, "n", false, "Only report the problems, do not repair them")
+
+	flagset.Parse(args)
+
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	archive, err := getActiveArchive()
+	if err != nil {
+		return err
+	}
+
+	nproblems := checkJournal("journal", journal, dryrun)
+	nproblems += checkJournal("archive", archive, dryrun)
+	nproblems += migrateShortIDs("journal", journal, dryrun)
+
+	// The archive is migrated first, so that the archived tasks keep their
+	// global index (their UID in the archive)
+	nmigrated, oldpaths, err := migrateGlobalIDs("archive", archive, dryrun)
+	if err != nil {
+		return err
+	}
+	nproblems += nmigrated
+	journal.Reserve(*archive)
+	nmigrated, journalOldpaths, err := migrateGlobalIDs("journal", journal, dryrun)
+	if err != nil {
+		return err
+	}
+	nproblems += nmigrated
+	oldpaths = append(oldpaths, journalOldpaths...)
+
+	if nproblems == 0 {
+		fmt.Println("No problem found")
+		return nil
+	}
+	if dryrun {
+		fmt.Printf("%d problem(s) found (use fsck without -n to repair)\n", nproblems)
+		return nil
+	}
+
+	err = archive.Save()
+	if err != nil {
+		return err
+	}
+	err = journal.Save()
+	if err != nil {
+		return err
+	}
+	// The former note files are removed only once both journals are saved
+	for _, notepath := range oldpaths {
+		err = os.Remove(notepath)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%d problem(s) repaired\n", nproblems)
+	return nil
+}
+
+// checkJournal prints the orphans and the cycles of parent relations of the
+// journal, and makes root tasks of the orphans and of the first task of each
+// cycle unless dryrun is true. Returns the number of problems.
+func checkJournal(name string, journal *todo.TaskJournal, dryrun bool) int {
+	var orphans []todo.Orphan
+	var cycles []todo.Cycle
+	if dryrun {
+		orphans = journal.Orphans()
+		cycles = journal.Cycles()
+	} else {
+		orphans = journal.RepairOrphans()
+		cycles = journal.RepairCycles()
+	}
+	for _, orphan := range orphans {
+		fmt.Printf("%s: %s\n", name, orphan.String())
+		if !dryrun {
+			fmt.Printf("%s: The task %d is now a root task\n", name, orphan.UIndex)
+		}
+	}
+	for _, cycle := range cycles {
+		fmt.Printf("%s: %s\n", name, cycle.String())
+		if !dryrun {
+			fmt.Printf("%s: The task %d is now a root task\n", name, cycle[0])
+		}
+	}
+	return len(orphans) + len(cycles)
+}
+
+// migrateShortIDs prints the tasks of the journal that have no unique short ID
+// (the tasks created by the former versions of todo), and gives them a short
+// ID unless dryrun is true. Returns the number of tasks to migrate.
+func migrateShortIDs(name string, journal *todo.TaskJournal, dryrun bool) int {
+	uindeces := journal.UnassignedShortIDs()
+	if !dryrun {
+		journal.AssignShortIDs()
+	}
+	for _, uindex := range uindeces {
+		task, _ := journal.GetTask(uindex)
+		if dryrun {
+			fmt.Printf("%s: The task %d has no unique short ID\n", name, uindex)
+		} else {
+			fmt.Printf("%s: The task %d is given the short ID %s\n", name, uindex, task.ShortID)
+		}
+	}
+	return len(uindeces)
+}
+
+// migrateGlobalIDs prints the tasks of the journal that have no GUID or whose
+// global index is already used (the tasks created by the former versions of
+// todo), and migrates them unless dryrun is true. Returns the number of tasks
+// to migrate and the former note files, to be removed once the journal is
+// saved (see TaskJournal.MigrateGlobalIDs).
+func migrateGlobalIDs(name string, journal *todo.TaskJournal, dryrun bool) (int, []string, error) {
+	uindeces := journal.UnmigratedGlobalIDs()
+	if dryrun {
+		for _, uindex := range uindeces {
+			fmt.Printf("%s: The task %d has no GUID or a global index already used\n", name, uindex)
+		}
+		return len(uindeces), nil, nil
+	}
+	_, oldpaths, err := journal.MigrateGlobalIDs()
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, uindex := range uindeces {
+		task, _ := journal.GetTask(uindex)
+		fmt.Printf("%s: The task %d is given the GUID %s and the global index %d\n", name, uindex, task.GUID, task.GIndex)
+	}
+	return len(uindeces), oldpaths, nil
+}
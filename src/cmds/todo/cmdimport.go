@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"galuma.net/todo"
+)
+
+// commandImport is the arguments parser of the command import
+func commandImport(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var format string
+	help := fmt.Sprintf("Format of the imported file, in: %s (default from the file extension)", formatNames())
+	flagset.StringVar(&format, "format", "", help)
+	var mapping string
+	help = "Mapping file of the columns of a foreign csv file (json file with the keys Columns, Status, DateLayout)"
+	flagset.StringVar(&mapping, "mapping", "", help)
+
+	flagset.Parse(args)
+
+	if flagset.NArg() != 1 {
+		flagset.Usage()
+		return errors.New("ERR: The file to import should be specified")
+	}
+	filepath := flagset.Arg(0)
+
+	exchangeFormat, err := getFormat(format, filepath)
+	if err != nil {
+		return err
+	}
+	if exchangeFormat.Parse == nil {
+		return fmt.Errorf("ERR: the format %s could not be imported", exchangeFormat.Name)
+	}
+	options := importOptions{
+		filepath: filepath,
+		mapping:  mapping,
+	}
+	return importTasks(exchangeFormat, options)
+}
+
+func importTasks(exchangeFormat exchangeFormat, options importOptions) error {
+	text, err := todo.LoadString(options.filepath)
+	if err != nil {
+		return err
+	}
+	tasks, notes, err := exchangeFormat.Parse(text, options)
+	if err != nil {
+		return err
+	}
+
+	journal, err := getActiveJournalToAdd()
+	if err != nil {
+		return err
+	}
+	uidmap, err := journal.ImportWithNotes(tasks, notes)
+	if err != nil {
+		return err
+	}
+	err = journal.Save()
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		imported, err := journal.GetTask(uidmap[task.UIndex])
+		if err == nil {
+			fmt.Println(imported.String())
+		}
+	}
+	fmt.Printf("%d tasks have been imported from the %s file: %s\n", len(tasks), exchangeFormat.Name, options.filepath)
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"galuma.net/todo"
+)
+
+// commandInit is the arguments parser of the command init
+func commandInit(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var contextName string
+	help := "Link the workspace to the context with name=string (default is a local workspace)"
+	flagset.StringVar(&contextName, "c", "", help)
+
+	flagset.Parse(args)
+
+	if len(flagset.Args()) > 1 {
+		msg := fmt.Sprintf("ERR: the arguments %v are not valid", flagset.Args())
+		return errors.New(msg)
+	}
+
+	dirpath, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if len(flagset.Args()) == 1 {
+		dirpath = flagset.Arg(0)
+	}
+	return initWorkspace(dirpath, contextName)
+}
+
+func initWorkspace(dirpath string, contextName string) error {
+	if contextName != "" {
+		config, err := todo.GetConfig()
+		if err != nil {
+			return err
+		}
+		if config.GetContext(contextName) == nil {
+			return fmt.Errorf("ERR: the context %s does not exist", contextName)
+		}
+	}
+
+	markerpath, err := todo.InitWorkspace(dirpath, contextName)
+	if err != nil {
+		return err
+	}
+	if contextName != "" {
+		fmt.Printf("The workspace %s is linked to the context %s\n", markerpath, contextName)
+	} else {
+		fmt.Printf("The local workspace %s has been created\n", markerpath)
+	}
+	fmt.Println("This workspace is the active context in this directory and its subdirectories")
+	return nil
+}
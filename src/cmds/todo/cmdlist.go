@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"time"
@@ -18,21 +19,54 @@ func commandList(cmdname string, args []string) error {
 	flagset.BoolVar(&tree, "t", false, "Tree representation of parent relations")
 	var report bool
 	flagset.BoolVar(&report, "r", false, "List a complete report (list, board, and notes")
+	var status string
+	flagset.StringVar(&status, "s", "", "List only the tasks with the specified status (comma separated list of labels)")
+
+	var treeOptions todo.TreeOptions
+	flagset.Var(&treeOptions.Root, "root", "Tree of the specified task only (implies -t)")
+	flagset.IntVar(&treeOptions.Depth, "depth", 0, "Number of levels of the tree (implies -t, all the levels if 0)")
+	flagset.BoolVar(&treeOptions.CollapseDone, "collapse-done", false, "Fold the done branches of the tree in one line (implies -t)")
 
 	var filepath string
-	flagset.StringVar(&filepath, "f", "", "Print the listing in the specified file")
+	flagset.StringVar(&filepath, "f", "", "Print the listing in the specified file (a pdf or html document if the extension is .pdf or .html)")
+
+	var allContexts bool
+	flagset.BoolVar(&allContexts, "all-contexts", false, "List the tasks of all the contexts")
+	var contexts string
+	flagset.StringVar(&contexts, "contexts", "", "List the tasks of the specified contexts (comma separated list of names)")
 
 	flagset.Parse(args)
 
-	journal, err := getActiveJournal()
+	config, err := todo.GetConfig()
 	if err != nil {
 		return err
 	}
 
-	config, err := todo.GetConfig()
+	if treeOptions.Depth < 0 {
+		return errors.New("ERR: the depth of the tree should be positive")
+	}
+	if treeOptions.Root != todo.NoUID || treeOptions.Depth > 0 || treeOptions.CollapseDone {
+		tree = true
+	}
+	filter, err := taskFilter(board, status)
 	if err != nil {
 		return err
 	}
+	if board || status != "" {
+		// The filtered tasks are listed instead of the report
+		treeOptions.Filter = filter
+		report = false
+	}
+
+	if isPDFPath(filepath) || isHTMLPath(filepath) {
+		if allContexts || contexts != "" {
+			return errors.New("ERR: the pdf and html outputs are not available for several contexts (see the command export)")
+		}
+		if status != "" || treeOptions.Root != todo.NoUID || treeOptions.Depth > 0 || treeOptions.CollapseDone {
+			return errors.New("ERR: the status filter and the tree options are not available for the pdf and html outputs")
+		}
+		return documentListing(filepath, board, tree, report)
+	}
 
 	// If the output is a file, then we deactivate temporarely the color
 	// rendering
@@ -49,23 +83,84 @@ func commandList(cmdname string, args []string) error {
 	}
 
 	var listing string
-	if board {
-		listing = journal.ListWithFilter(todo.TaskFilterOnBoard)
-	} else if report {
-		listing = journal.Report()
+	if allContexts || contexts != "" {
+		listing, err = contextsListing(contexts, filter, tree, report, treeOptions)
 	} else {
-		if tree {
-			listing = journal.Tree()
-		} else {
-			listing = journal.List()
-		}
+		listing, err = journalListing(filter, tree, report, treeOptions)
+	}
+	if err == nil {
+		_, err = printlist(listing)
 	}
-
-	_, err = printlist(listing)
 	config.Parameters.WithColor = colorflag
 	return err
 }
 
+// journalListing returns the listing of the tasks of the active journal that
+// satisfy the filter: a tree (restricted by the tree options) if tree is true,
+// and a complete report if report is true.
+func journalListing(filter todo.TaskFilter, tree bool, report bool, treeOptions todo.TreeOptions) (string, error) {
+	journal, err := getActiveJournal()
+	if err != nil {
+		return "", err
+	}
+	if tree {
+		treeOptions.Root, err = journal.Resolve(treeOptions.Root)
+		if err != nil {
+			return "", err
+		}
+		return journal.TreeWithOptions(treeOptions)
+	}
+	if report {
+		return journal.Report(), nil
+	}
+	return journal.ListWithFilter(filter), nil
+}
+
+// documentListing prints the listing in a pdf or html document, whose sections
+// are determined by the options of the list command.
+func documentListing(fpath string, board bool, tree bool, report bool) error {
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	sections := []todo.ReportSection{todo.SectionList}
+	if board {
+		sections = []todo.ReportSection{todo.SectionBoard}
+	} else if report {
+		sections = todo.ReportSections
+	} else if tree {
+		sections = []todo.ReportSection{todo.SectionTree}
+	}
+	title := fmt.Sprintf("TODO list of the context %s", config.GetActiveContext().Name)
+	if isHTMLPath(fpath) {
+		return printhtml(fpath, journal, title, sections)
+	}
+	return printpdf(fpath, journal, title, sections)
+}
+
+// contextsListing returns the listing of the tasks of the specified contexts
+// that satisfy the filter (see journalListing).
+func contextsListing(contexts string, filter todo.TaskFilter, tree bool, report bool, treeOptions todo.TreeOptions) (string, error) {
+	if report {
+		return "", errors.New("ERR: the report is not available for several contexts")
+	}
+	if treeOptions.Root != todo.NoUID {
+		return "", errors.New("ERR: the option -root is not available for several contexts")
+	}
+	journals, err := getContextJournals(contexts)
+	if err != nil {
+		return "", err
+	}
+	if tree {
+		return journals.TreeWithOptions(treeOptions), nil
+	}
+	return journals.ListWithFilter(filter), nil
+}
+
 type printer func(text string) (int, error)
 
 func stdOutPrinter() printer {
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"galuma.net/todo"
+)
+
+// commandMove is the arguments parser of the command move
+func commandMove(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var contextName string
+	flagset.StringVar(&contextName, "c", "", "Name of the destination context")
+	var copy bool
+	flagset.BoolVar(&copy, "copy", false, "Copy the tasks instead of moving them")
+
+	flagset.Parse(args)
+
+	if contextName == "" {
+		flagset.Usage()
+		return errors.New("ERR: The destination context should be specified (-c)")
+	}
+
+	var indeces todo.TaskIDArray
+	for _, arg := range flagset.Args() {
+		var argIndeces todo.TaskIDArray
+		err := argIndeces.Set(arg)
+		if err != nil {
+			return fmt.Errorf("ERR: the argument %s is not a list of indeces (%s)", arg, err)
+		}
+		indeces = append(indeces, argIndeces...)
+	}
+	if len(indeces) == 0 {
+		flagset.Usage()
+		return errors.New("ERR: The tasks should be specified (comma separated list of indeces)")
+	}
+
+	return transferTasks(indeces, contextName, copy)
+}
+
+func transferTasks(indeces todo.TaskIDArray, contextName string, copy bool) error {
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	context := config.GetContext(contextName)
+	if context == nil {
+		return fmt.Errorf("ERR: the context %s does not exist", contextName)
+	}
+	if context.JournalPath() == config.GetActiveContext().JournalPath() {
+		return fmt.Errorf("ERR: the context %s is the active context", contextName)
+	}
+
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	target, err := loadJournal(context.JournalPath())
+	if err != nil {
+		return err
+	}
+	targetArchive, err := loadJournal(context.ArchivePath())
+	if err != nil {
+		return err
+	}
+	target.Reserve(*targetArchive)
+
+	indeces, err = journal.ResolveAll(indeces)
+	if err != nil {
+		return err
+	}
+	uidmap, notepaths, err := journal.Transfer(target, indeces, copy)
+	if err != nil {
+		return err
+	}
+	err = target.Save()
+	if err != nil {
+		return err
+	}
+	if !copy {
+		err = journal.Save()
+		if err != nil {
+			return err
+		}
+	}
+	// The source notes are removed only once both journals are saved
+	for _, notepath := range notepaths {
+		err = os.Remove(notepath)
+		if err != nil {
+			return err
+		}
+	}
+
+	action := "moved"
+	if copy {
+		action = "copied"
+	}
+	olduids := make(todo.TaskIDArray, 0, len(uidmap))
+	for olduid := range uidmap {
+		olduids = append(olduids, olduid)
+	}
+	sort.Slice(olduids, func(i, j int) bool { return olduids[i] < olduids[j] })
+	for _, olduid := range olduids {
+		fmt.Printf("Task %d %s to the context %s with the usage index: %d\n", olduid, action, contextName, uidmap[olduid])
+	}
+	return nil
+}
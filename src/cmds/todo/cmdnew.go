@@ -16,6 +16,8 @@ func commandNew(cmdname string, args []string) error {
 	flagset.StringVar(&text, "t", "", "text of the task")
 	var parentUID todo.TaskID
 	flagset.Var(&parentUID, "p", "parent task (default is: no parent)")
+	var due string
+	flagset.StringVar(&due, "d", "", "due date of the task (YYYY-MM-DD)")
 	flagset.Parse(args)
 
 	if text == "" {
@@ -23,19 +25,34 @@ func commandNew(cmdname string, args []string) error {
 		return errors.New("ERR: The text should be specified")
 	}
 
-	journal, err := getActiveJournal()
+	var dueDate int64
+	if due != "" {
+		var err error
+		dueDate, err = todo.ParseDate(due)
+		if err != nil {
+			return err
+		}
+	}
+
+	journal, err := getActiveJournalToAdd()
 	if err != nil {
 		return err
 	}
 
 	task := journal.New(text)
+	task.Due = dueDate
 
 	if parentUID != todo.NoUID {
+		parentUID, err = journal.Resolve(parentUID)
+		if err != nil {
+			return err
+		}
 		parentTask, err := journal.GetTask(parentUID)
 		if err != nil {
 			return err
 		}
 		task.ParentID = parentTask.UIndex
+		updateParentStatus(journal)
 	}
 
 	err = journal.Save()
@@ -40,6 +40,10 @@ func editNote(index todo.TaskID) error {
 	if err != nil {
 		return err
 	}
+	index, err = journal.Resolve(index)
+	if err != nil {
+		return err
+	}
 
 	notepath, err := journal.GetOrCreateNoteFile(index)
 	if err != nil {
@@ -55,6 +59,10 @@ func viewNote(index todo.TaskID) error {
 	if err != nil {
 		return err
 	}
+	index, err = journal.Resolve(index)
+	if err != nil {
+		return err
+	}
 
 	notepath, err := journal.GetNoteFile(index)
 	if err != nil {
@@ -77,6 +85,10 @@ func deleteNote(index todo.TaskID) error {
 	if err != nil {
 		return err
 	}
+	index, err = journal.Resolve(index)
+	if err != nil {
+		return err
+	}
 
 	err = journal.DeleteNoteFile(index)
 	if err != nil {
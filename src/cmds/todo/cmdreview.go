@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"galuma.net/todo"
+)
+
+// reviewKeys gives the key to type to choose each review action
+var reviewKeys = map[todo.ReviewAction]string{
+	todo.ActionKeep:    "k",
+	todo.ActionNext:    "n",
+	todo.ActionArchive: "a",
+	todo.ActionUnboard: "r",
+	todo.ActionDelete:  "d",
+}
+
+const reviewQuitKey = "q"
+
+// reviewDecision is the action chosen for a review item
+type reviewDecision struct {
+	item   todo.ReviewItem
+	action todo.ReviewAction
+}
+
+// commandReview is the arguments parser of the command review
+func commandReview(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var age int
+	flagset.IntVar(&age, "age", 0, "Age (in days) from which a task is stale (default to the parameter ReviewAge)")
+
+	flagset.Parse(args)
+
+	config, err := todo.GetConfig()
+	if err != nil {
+		return err
+	}
+	if age <= 0 {
+		age = config.Parameters.ReviewAge
+	}
+
+	journal, err := getActiveJournal()
+	if err != nil {
+		return err
+	}
+	archive, err := getActiveArchive()
+	if err != nil {
+		return err
+	}
+	items, err := journal.ReviewItems(*archive, time.Now().Unix(), age)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to review")
+		return nil
+	}
+
+	decisions := promptReview(items, os.Stdin, os.Stdout)
+	return applyReview(journal, archive, decisions)
+}
+
+// promptReview asks for an action for each item, until all the items are
+// reviewed or the user quits the review. Returns the decisions (except keep).
+func promptReview(items []todo.ReviewItem, input io.Reader, output io.Writer) []reviewDecision {
+	decisions := make([]reviewDecision, 0)
+	scanner := bufio.NewScanner(input)
+	for i, item := range items {
+		actions := item.Actions()
+		choices := make([]string, 0, len(actions)+1)
+		for _, action := range actions {
+			choices = append(choices, fmt.Sprintf("[%s] %s", reviewKeys[action], action.Label()))
+		}
+		choices = append(choices, fmt.Sprintf("[%s] quit", reviewQuitKey))
+
+		fmt.Fprintf(output, "\n(%d/%d) %s\n", i+1, len(items), item.String())
+		for {
+			fmt.Fprintf(output, "Action? %s (default: %s): ", strings.Join(choices, " "), actions[0].Label())
+			if !scanner.Scan() {
+				fmt.Fprintln(output)
+				return decisions
+			}
+			key := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if key == reviewQuitKey {
+				return decisions
+			}
+			action, valid := actions[0], key == ""
+			for _, candidate := range actions {
+				if key == reviewKeys[candidate] {
+					action, valid = candidate, true
+				}
+			}
+			if valid {
+				if action != todo.ActionKeep {
+					decisions = append(decisions, reviewDecision{item, action})
+				}
+				break
+			}
+			fmt.Fprintf(output, "The action %s is not valid\n", key)
+		}
+	}
+	return decisions
+}
+
+// applyReview applies the decisions to the journal and to the archive, and
+// saves them once all the decisions are applied.
+func applyReview(journal *todo.TaskJournal, archive *todo.TaskJournal, decisions []reviewDecision) error {
+	if len(decisions) == 0 {
+		fmt.Println("\nNo change")
+		return nil
+	}
+	fmt.Println()
+	notes := make([]string, 0)
+	for _, decision := range decisions {
+		uindex := decision.item.Task.UIndex
+		var err error
+		switch {
+		case decision.item.Kind == todo.ReviewNote:
+			notes = append(notes, decision.item.NotePath)
+		case decision.action == todo.ActionNext:
+			var task *todo.Task
+			task, err = journal.GetTask(uindex)
+			if err == nil {
+				err = task.NextStatus()
+			}
+			if err == nil {
+				fmt.Printf("Task of index %d is now %s\n", uindex, task.Status.Label())
+			}
+		case decision.action == todo.ActionUnboard:
+			err = journal.RemoveFromBoard(uindex)
+			if err == nil {
+				fmt.Printf("Task of index %d has been removed from board\n", uindex)
+			}
+		case decision.action == todo.ActionArchive:
+			var uids map[todo.TaskID]todo.TaskID
+			uids, err = journal.Move(archive, todo.TaskIDArray{uindex}, archiveUID)
+			if err == nil {
+				fmt.Printf("Task %d moved to the archive with a new usage index: %d\n", uindex, uids[uindex])
+			}
+		case decision.action == todo.ActionDelete:
+			// The note of the task is deleted with the task (once the journal
+			// is saved), otherwise it would be reported as an orphaned note
+			var notepath string
+			notepath, err = journal.GetNoteFile(uindex)
+			if err == nil {
+				_, err = journal.Delete(uindex)
+			}
+			if err == nil {
+				fmt.Printf("Task of index %d has been deleted\n", uindex)
+				if notepath != "" {
+					notes = append(notes, notepath)
+				}
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	updateParentStatus(journal)
+	err := archive.Save()
+	if err != nil {
+		return err
+	}
+	err = journal.Save()
+	if err != nil {
+		return err
+	}
+	for _, notepath := range notes {
+		err = os.Remove(notepath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Note %s has been deleted\n", notepath)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"galuma.net/todo"
+)
+
+// commandStats is the arguments parser of the command stats
+func commandStats(cmdname string, args []string) error {
+	flagset := flag.NewFlagSet(cmdname, flag.ExitOnError)
+
+	var weeks int
+	flagset.IntVar(&weeks, "w", 8, "Size of the window of the throughput and of the burnup chart (number of weeks)")
+	var allContexts bool
+	flagset.BoolVar(&allContexts, "all-contexts", false, "Compute the statistics of all the contexts")
+	var contexts string
+	flagset.StringVar(&contexts, "contexts", "", "Compute the statistics of the specified contexts (comma separated list of names)")
+
+	flagset.Parse(args)
+
+	if weeks < 1 {
+		return errors.New("ERR: the window should be at least one week")
+	}
+
+	var stats todo.TaskStats
+	if allContexts || contexts != "" {
+		journals, err := getContextJournals(contexts)
+		if err != nil {
+			return err
+		}
+		for _, contextJournal := range journals {
+			archive, err := loadJournal(contextJournal.Context.ArchivePath())
+			if err != nil {
+				return err
+			}
+			stats.Add(contextJournal.Context.Name, contextJournal.Journal, *archive)
+		}
+	} else {
+		config, err := todo.GetConfig()
+		if err != nil {
+			return err
+		}
+		journal, err := getActiveJournal()
+		if err != nil {
+			return err
+		}
+		archive, err := getActiveArchive()
+		if err != nil {
+			return err
+		}
+		stats.Add(config.GetActiveContext().Name, *journal, *archive)
+	}
+
+	fmt.Println(stats.Report(time.Now().Unix(), weeks))
+	return nil
+}
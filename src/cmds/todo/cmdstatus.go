@@ -18,14 +18,15 @@ func commandStatus(cmdname string, args []string) error {
 	flagset.Var(&prev, "p", "Change to their previous status the specified tasks (comma separated list of indices)")
 	var info todo.TaskIDArray
 	flagset.Var(&info, "i", "Display the complete status of the specified tasks (comma separated list of indices)")
+	recursive := recursiveFlag(flagset)
 
 	flagset.Parse(args)
 
 	if len(next) > 0 {
-		return modifyStatus(next, modifierNext)
+		return modifyStatus(next, modifierNext, *recursive)
 	}
 	if len(prev) > 0 {
-		return modifyStatus(prev, modifierPrevious)
+		return modifyStatus(prev, modifierPrevious, *recursive)
 	}
 	if len(info) > 0 {
 		return infoStatus(info)
@@ -38,18 +39,24 @@ func commandStatus(cmdname string, args []string) error {
 type statusModifier func(task *todo.Task) error
 
 func modifierNext(task *todo.Task) error {
-	return task.Status.Next()
+	return task.NextStatus()
 }
 
 func modifierPrevious(task *todo.Task) error {
-	return task.Status.Previous()
+	return task.PreviousStatus()
 }
 
-func modifyStatus(indeces todo.TaskIDArray, modifier statusModifier) error {
+// modifyStatus changes the status of the tasks with the modifier. If recursive
+// is true, then their descendants are also moved toward their new status.
+func modifyStatus(indeces todo.TaskIDArray, modifier statusModifier, recursive bool) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
+	indeces, err = journal.ResolveAll(indeces)
+	if err != nil {
+		return err
+	}
 	for _, index := range indeces {
 		task, err := journal.GetTask(index)
 		if err != nil {
@@ -61,17 +68,35 @@ func modifyStatus(indeces todo.TaskIDArray, modifier statusModifier) error {
 				fmt.Println(msg)
 			} else {
 				fmt.Println(task.String())
+				if recursive {
+					cascadeStatus(journal, index, modifier)
+				}
 			}
 		}
 	}
+	updateParentStatus(journal)
 	return journal.Save()
 }
 
+// cascadeStatus moves the descendants of the task toward its new status (see
+// TaskJournal.CascadeStatus)
+func cascadeStatus(journal *todo.TaskJournal, uindex todo.TaskID, modifier statusModifier) {
+	changed, _ := journal.CascadeStatus(uindex, modifier)
+	for _, uid := range changed {
+		descendant, _ := journal.GetTask(uid)
+		fmt.Println(descendant.String())
+	}
+}
+
 func infoStatus(indeces todo.TaskIDArray) error {
 	journal, err := getActiveJournal()
 	if err != nil {
 		return err
 	}
+	indeces, err = journal.ResolveAll(indeces)
+	if err != nil {
+		return err
+	}
 	fmt.Println()
 	for _, uindex := range indeces {
 		info, err := journal.GetTaskInfo(uindex)
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"galuma.net/todo"
+)
+
+// exportOptions gathers the options of the export command that are used by
+// the export functions.
+type exportOptions struct {
+	contextName string          // name of the exported context
+	filter      todo.TaskFilter // filter of the exported tasks
+	notes       bool            // true if the notes should be exported
+	columns     []string        // columns of the tabular formats
+}
+
+// importOptions gathers the options of the import command that are used by
+// the parse functions.
+type importOptions struct {
+	filepath string // path of the imported file
+	mapping  string // path of the mapping file of the tabular formats
+}
+
+// exportFunction creates the representation of the journal in a given format
+type exportFunction func(journal *todo.TaskJournal, options exportOptions) (string, error)
+
+// parseFunction reads the tasks (and the contents of their notes) from a text
+// in a given format. The tasks are then imported in the journal with
+// TaskJournal.ImportWithNotes.
+type parseFunction func(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error)
+
+// exchangeFormat defines a file format used to export and import tasks (the
+// Parse function is nil if the format could not be imported)
+type exchangeFormat struct {
+	Name      string
+	Extension string
+	Export    exportFunction
+	Parse     parseFunction
+}
+
+var exchangeFormats = []exchangeFormat{
+	{Name: "todotxt", Extension: ".txt", Export: exportTodoTxt, Parse: parseTodoTxt},
+	{Name: "markdown", Extension: ".md", Export: exportMarkdown, Parse: parseMarkdown},
+	{Name: "ical", Extension: ".ics", Export: exportICalendar, Parse: parseICalendar},
+	{Name: "csv", Extension: ".csv", Export: exportCSV, Parse: parseCSV},
+	{Name: "taskwarrior", Extension: ".json", Export: exportTaskwarrior, Parse: parseTaskwarrior},
+	{Name: "org", Extension: ".org", Export: exportOrg, Parse: parseOrg},
+	{Name: "html", Extension: ".html", Export: exportHTML},
+	{Name: "dot", Extension: ".dot", Export: exportDOT},
+}
+
+// formatNames returns the list of the names of the exchange formats
+func formatNames() []string {
+	names := make([]string, len(exchangeFormats))
+	for i, format := range exchangeFormats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// getFormat returns the exchange format of the given name. If the name is
+// blank, then the format is determined from the extension of the file path.
+func getFormat(name string, fpath string) (exchangeFormat, error) {
+	ext := strings.ToLower(filepath.Ext(fpath))
+	for _, format := range exchangeFormats {
+		if format.Name == name || (name == "" && ext != "" && format.Extension == ext) {
+			return format, nil
+		}
+	}
+	if name == "" {
+		return exchangeFormat{}, fmt.Errorf("ERR: the format should be specified (in: %s)", formatNames())
+	}
+	return exchangeFormat{}, fmt.Errorf("ERR: the format %s is not defined (should be in: %s)", name, formatNames())
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the todo.txt format
+
+func exportTodoTxt(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	return journal.TodoTxt(options.filter, options.contextName), nil
+}
+
+func parseTodoTxt(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	tasks, err := todo.ParseTodoTxt(text)
+	return tasks, nil, err
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the Markdown format
+
+func exportMarkdown(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	title := fmt.Sprintf("TODO list of the context %s", options.contextName)
+	return journal.Markdown(title, options.filter, options.notes), nil
+}
+
+func parseMarkdown(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	return todo.ParseMarkdown(text)
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the iCalendar format
+
+func exportICalendar(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	return journal.ICalendar(options.filter), nil
+}
+
+func parseICalendar(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	return todo.ParseICalendar(text)
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the CSV format
+
+func exportCSV(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	columns := options.columns
+	if options.notes && len(columns) == 0 {
+		columns = append(append(columns, todo.DefaultCSVColumns...), "note")
+	}
+	return journal.CSV(options.filter, columns)
+}
+
+func parseCSV(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	var mapping todo.CSVMapping
+	if options.mapping != "" {
+		var err error
+		mapping, err = todo.LoadCSVMapping(options.mapping)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return todo.ParseCSV(text, mapping)
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the Taskwarrior JSON format
+
+func exportTaskwarrior(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	return journal.TaskwarriorJSON(options.filter)
+}
+
+func parseTaskwarrior(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	return todo.ParseTaskwarriorJSON(text)
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the Org-mode format
+
+func exportOrg(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	title := fmt.Sprintf("TODO list of the context %s", options.contextName)
+	return journal.Org(title, options.filter), nil
+}
+
+func parseOrg(text string, options importOptions) (todo.TaskArray, map[todo.TaskID]string, error) {
+	return todo.ParseOrg(text)
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the HTML format (export only)
+
+func exportHTML(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	title := fmt.Sprintf("TODO list of the context %s", options.contextName)
+	return journal.HTMLReport(title, todo.ReportSections, options.filter), nil
+}
+
+// -----------------------------------------------------------------------
+// Implementation of the Graphviz DOT format (export only)
+
+func exportDOT(journal *todo.TaskJournal, options exportOptions) (string, error) {
+	title := fmt.Sprintf("TODO list of the context %s", options.contextName)
+	return journal.DOT(title, options.filter), nil
+}
@@ -1,6 +1,12 @@
 package main
 
-import "galuma.net/todo"
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"galuma.net/todo"
+)
 
 var (
 	activeJournal *todo.TaskJournal
@@ -37,3 +43,99 @@ func getActiveArchive() (*todo.TaskJournal, error) {
 	}
 	return loadJournal(cfg.GetActiveContext().ArchivePath())
 }
+
+// getActiveJournalToAdd returns the active journal, whose new tasks can not
+// take the global identifiers of the tasks of the archive (see
+// TaskJournal.Reserve), so that they can be archived later.
+func getActiveJournalToAdd() (*todo.TaskJournal, error) {
+	journal, err := getActiveJournal()
+	if err != nil {
+		return nil, err
+	}
+	archive, err := getActiveArchive()
+	if err != nil {
+		return nil, err
+	}
+	journal.Reserve(*archive)
+	return journal, nil
+}
+
+// getContextJournals returns the journals of the contexts specified by the
+// comma separated list of names (all the contexts if names is empty).
+func getContextJournals(names string) (todo.ContextJournalArray, error) {
+	cfg, err := todo.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	var contextNames []string
+	if names != "" {
+		contextNames = strings.Split(names, ",")
+	}
+	return cfg.LoadJournals(contextNames)
+}
+
+// updateParentStatus derives the status of the parent tasks of the journal
+// from the status of their children, if the parameter AutoParentStatus is set,
+// and prints the tasks whose status has changed.
+func updateParentStatus(journal *todo.TaskJournal) {
+	cfg, err := todo.GetConfig()
+	if err != nil || !cfg.Parameters.AutoParentStatus {
+		return
+	}
+	for _, uindex := range journal.UpdateParentStatus() {
+		task, _ := journal.GetTask(uindex)
+		fmt.Printf("The status of the task %d is derived from its children: %s\n", uindex, task.Status.Label())
+	}
+}
+
+// recursiveFlag defines the options -R and -recursive of the flagset, to apply
+// a command to the specified tasks and all their descendants.
+func recursiveFlag(flagset *flag.FlagSet) *bool {
+	recursive := new(bool)
+	usage := "Apply also to all the descendants of the specified tasks"
+	flagset.BoolVar(recursive, "R", false, usage)
+	flagset.BoolVar(recursive, "recursive", false, usage+" (same as -R)")
+	return recursive
+}
+
+// withDescendants returns the UIDs of the tasks specified by the indeces (UIDs
+// or short IDs) together with the UIDs of all the descendants of the tasks in
+// the journal if recursive is true.
+func withDescendants(journal *todo.TaskJournal, indeces todo.TaskIDArray, recursive bool) (todo.TaskIDArray, error) {
+	indeces, err := journal.ResolveAll(indeces)
+	if err != nil || !recursive {
+		return indeces, err
+	}
+	return journal.WithDescendants(indeces)
+}
+
+// taskFilter returns the filter of the tasks on board if board is true, and
+// whose status is in the comma separated list of status labels (if not blank).
+func taskFilter(board bool, status string) (todo.TaskFilter, error) {
+	statusList := make([]todo.TaskStatus, 0)
+	if status != "" {
+		for _, label := range strings.Split(status, ",") {
+			var s todo.TaskStatus
+			err := s.Value(label)
+			if err != nil {
+				return nil, err
+			}
+			statusList = append(statusList, s)
+		}
+	}
+	filter := func(task todo.Task) bool {
+		if board && !task.OnBoard {
+			return false
+		}
+		if len(statusList) == 0 {
+			return true
+		}
+		for _, s := range statusList {
+			if task.Status == s {
+				return true
+			}
+		}
+		return false
+	}
+	return filter, nil
+}
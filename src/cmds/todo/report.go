@@ -2,95 +2,59 @@ package main
 
 import (
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"galuma.net/todo"
 )
 
-// printfile prints the text content to a file with path fpath. The output format
-// is a plain text. If fpath has a pdf extention then the content is printed
-// also in a pdf file (in this case, the function results in two files: the plain
-// text file and a pdf file)
-func printfile(fpath string, text string) (int, error) {
+// isPDFPath returns true if the file path has a pdf extension
+func isPDFPath(fpath string) bool {
+	return strings.ToLower(filepath.Ext(fpath)) == ".pdf"
+}
 
-	var txtpath string
-	var pdfpath string
-	pdf := false
-	ext := filepath.Ext(fpath)
-	if ext == ".pdf" {
-		noxpath := strings.TrimSuffix(fpath, ext)
-		txtpath = fmt.Sprintf("%s.txt", noxpath)
-		pdfpath = fpath
-		pdf = true
-	} else {
-		txtpath = fpath
-	}
+// isHTMLPath returns true if the file path has a html extension
+func isHTMLPath(fpath string) bool {
+	ext := strings.ToLower(filepath.Ext(fpath))
+	return ext == ".html" || ext == ".htm"
+}
 
-	fw, err := os.Create(txtpath)
-	defer fw.Close()
+// printfile prints the text content to a file with path fpath. The output format
+// is a plain text.
+func printfile(fpath string, text string) (int, error) {
+	fw, err := os.Create(fpath)
 	if err != nil {
 		return 0, err
 	}
+	defer fw.Close()
 	n, err := fw.WriteString(text)
 	if err != nil {
 		return 0, err
 	}
-	fmt.Printf("The todo list has been printed in the txt file: %s\n", txtpath)
-
-	if pdf {
-		err = txt2pdf(txtpath, pdfpath)
-		if err != nil {
-			err = fmt.Errorf("ERR: an error occurs during pdf creation: %s", err.Error())
-		} else {
-			fmt.Printf("The todo list has been printed in the pdf file: %s\n", pdfpath)
-		}
-	}
-
-	return n, err
+	fmt.Printf("The todo list has been printed in the txt file: %s\n", fpath)
+	return n, nil
 }
 
-func txt2pdf(txtpath, pdfpath string) error {
-	// To create a pdf file from a txt file, we use the external program
-	// cupsfilter, available on every linux system. The typical usage is:
-	//
-	// $ cupsfilter <txtpath> -o cpi=14 -o lpi=8 -o landscape > <pdfpath>
-	//
-	cmd := exec.Command(
-		"cupsfilter", txtpath,
-		"-o", "cpi=14",
-		"-o", "lpi=8",
-		"-o", "landscape")
-
-	fw, err := os.Create(pdfpath)
-	defer fw.Close()
+// printpdf prints the given sections of the report of the journal in a pdf
+// file with path fpath. The pdf document is created natively (no external
+// program is required).
+func printpdf(fpath string, journal *todo.TaskJournal, title string, sections []todo.ReportSection) error {
+	err := todo.WriteBytes(fpath, journal.PDFReport(title, sections))
 	if err != nil {
-		return err
+		return fmt.Errorf("ERR: an error occurs during pdf creation: %s", err.Error())
 	}
+	fmt.Printf("The todo list has been printed in the pdf file: %s\n", fpath)
+	return nil
+}
 
-	cmd.Stdout = fw
-	cmd.Stderr = debugFilter{writer: os.Stderr}
-	err = cmd.Start()
+// printhtml prints the given sections of the report of the journal in a
+// self-contained html file with path fpath.
+func printhtml(fpath string, journal *todo.TaskJournal, title string, sections []todo.ReportSection) error {
+	err := todo.WriteBytes(fpath, []byte(journal.HTMLReport(title, sections, todo.TaskFilterAll)))
 	if err != nil {
 		return err
 	}
-	return cmd.Wait()
-}
-
-// debugFilter is an implementation of io.Writer used to filter the debug output
-// of the function txt2pdf.
-type debugFilter struct {
-	writer io.Writer
-}
-
-// Write implements the io.Writer interface so that debugFilter is a Writer
-func (f debugFilter) Write(p []byte) (int, error) {
-	s := string(p[:])
-	if strings.HasPrefix(s, "DEBUG: ") {
-		// We return n as if the good number of characters was written but we
-		// don't print the line in fact
-		return len(p), nil
-	}
-	return f.writer.Write(p)
+	fmt.Printf("The todo list has been printed in the html file: %s\n", fpath)
+	return nil
 }
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package main
+
+// queryTerminalWidth returns 0: the size of the terminal can not be queried on
+// this system (the width is given by $COLUMNS or the option -w).
+func queryTerminalWidth() int {
+	return 0
+}
@@ -0,0 +1,23 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// queryTerminalWidth returns the number of columns of the terminal attached to
+// the standard output, or 0 if the output is not a terminal.
+func queryTerminalWidth() int {
+	var winsize struct {
+		rows, cols, xpixel, ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(),
+		uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&winsize)))
+	if errno != 0 {
+		return 0
+	}
+	return int(winsize.cols)
+}
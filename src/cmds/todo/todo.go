@@ -11,13 +11,33 @@ import (
 var commands = todo.CommandList{
 	{Name: "add", Description: "Create a new task", Parser: commandNew},
 	{Name: "list", Description: "Print the list of tasks", Parser: commandList},
+	{Name: "stats", Description: "Print statistics on the tasks (throughput, burnup)", Parser: commandStats},
+	{Name: "agenda", Description: "Print the agenda of the tasks (created, started, done and due dates)", Parser: commandAgenda},
+	{Name: "calendar", Description: "Print the calendar of the tasks of a month", Parser: commandCalendar},
+	{Name: "review", Description: "Review interactively the stale tasks, the board and the done tasks", Parser: commandReview},
 	{Name: "status", Description: "Change the status of tasks", Parser: commandStatus},
 	{Name: "board", Description: "Append/Remove tasks on/from the board", Parser: commandBoard},
 	{Name: "note", Description: "Edit/View the note associated to a task", Parser: commandNote},
 	{Name: "child", Description: "Make tasks be children of a parent task", Parser: commandChild},
 	{Name: "delete", Description: "Delete tasks (definitely or in archive)", Parser: commandDelete},
 	{Name: "archive", Description: "Archive/Restore tasks", Parser: commandArchive},
+	{Name: "move", Description: "Move/Copy tasks to another context", Parser: commandMove},
+	{Name: "export", Description: "Export tasks in a file format", Parser: commandExport},
+	{Name: "import", Description: "Import tasks from a file", Parser: commandImport},
+	{Name: "fsck", Description: "Check and repair the parent relations of the tasks, and migrate their identifiers", Parser: commandFsck},
 	{Name: "config", Description: "Manage de configuration", Parser: commandConfig},
+	{Name: "init", Description: "Create a workspace in the current directory", Parser: commandInit},
+}
+
+// commandNames is the list of the names of the commands (initialized from
+// commands in init, to avoid an initialization cycle with the parsers that use
+// it)
+var commandNames []string
+
+func init() {
+	for _, command := range commands {
+		commandNames = append(commandNames, command.Name)
+	}
 }
 
 func getConfig() *todo.Config {
@@ -29,10 +49,61 @@ func getConfig() *todo.Config {
 	return cfg
 }
 
+// parseGlobalOptions processes the options specified before the command name
+// (--config <dirpath>, to specify the configuration root directory) and
+// removes them from the command line arguments.
+func parseGlobalOptions() error {
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		if !strings.HasPrefix(arg, "-") || contains(helpOptions, arg) {
+			args = append(args, os.Args[i:]...)
+			break
+		}
+		if arg == "--config" || arg == "-config" {
+			if i+1 >= len(os.Args) {
+				return fmt.Errorf("ERR: the option %s requires a directory path", arg)
+			}
+			i++
+			todo.SetConfigRoot(os.Args[i])
+		} else if strings.HasPrefix(arg, "--config=") || strings.HasPrefix(arg, "-config=") {
+			todo.SetConfigRoot(arg[strings.Index(arg, "=")+1:])
+		} else {
+			return fmt.Errorf("ERR: the option %s is not valid", arg)
+		}
+	}
+	os.Args = args
+	return nil
+}
+
+var helpOptions = []string{"-help", "--help", "-h"}
+
+// configCommands are the commands that ignore an invalid override of the
+// active context (TODO_CONTEXT or a workspace marker), so that it can be fixed
+// with todo itself.
+var configCommands = []string{"config", "init"}
+
+func contains(sarray []string, sitem string) bool {
+	for _, item := range sarray {
+		if item == sitem {
+			return true
+		}
+	}
+	return false
+}
+
 func main() {
+	err := parseGlobalOptions()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(os.Args) > 1 && contains(configCommands, os.Args[1]) {
+		todo.IgnoreInvalidContextOverride(true)
+	}
 	app := todo.NewCommandParser("todo", commands)
 	app.SetDefaultCmdOptions(strings.Fields(getConfig().Parameters.DefaultCommand))
-	err := app.ArgParse()
+	err = app.ArgParse()
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
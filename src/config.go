@@ -6,14 +6,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
 	// configDirname is the directory name of the configuration (relative to user HOME)
 	configDirname = ".config/galuma/todogo"
+	// xdgDirname is the directory name of the configuration (relative to the
+	// XDG base directories XDG_CONFIG_HOME and XDG_DATA_HOME)
+	xdgDirname = "galuma/todogo"
 	// configFilename is the base name (relative to configDirname) of the configuration file
 	configFilename = "config.json"
 
+	// HomeEnvVariable is the environment variable that specifies the root
+	// directory of the configuration and of the contexts workspaces
+	HomeEnvVariable = "TODOGO_HOME"
+
 	// defaultContextName is the name (and relative path) of the default context
 	defaultContextName = "default"
 
@@ -22,15 +30,109 @@ const (
 )
 
 var (
-	// cfgdirpath is the absolute path of the configuration directory
-	cfgdirpath = filepath.Join(os.Getenv("HOME"), configDirname)
-	// cfgfilepath is the absolute path to the configuration file
-	cfgfilepath = filepath.Join(cfgdirpath, configFilename)
+	// cfgrootdir is the configuration root directory explicitly specified
+	// using SetConfigRoot (blank if not specified)
+	cfgrootdir string
 
 	// userConfig is a pointer to the current configuration (obtained using GetConfig)
 	userConfig *Config
+
+	// ignoreInvalidOverride is true if GetConfig should ignore an invalid
+	// override of the active context (see IgnoreInvalidContextOverride)
+	ignoreInvalidOverride bool
 )
 
+// SetConfigRoot specifies explicitly the configuration root directory to be
+// used by GetConfig (e.g. from a command line option). It has precedence over
+// the environment variables.
+func SetConfigRoot(dirpath string) {
+	cfgrootdir = dirpath
+	userConfig = nil
+	statusRenderingFunction = nil
+}
+
+// IgnoreInvalidContextOverride specifies if GetConfig should ignore an invalid
+// override of the active context (the environment variable TODO_CONTEXT or a
+// workspace marker that refers to an unknown context) instead of failing. It is
+// used by the commands that edit the configuration, so that an invalid
+// override does not prevent the user from fixing it. A valid override is
+// still applied.
+func IgnoreInvalidContextOverride(ignore bool) {
+	ignoreInvalidOverride = ignore
+	userConfig = nil
+}
+
+// ConfigRootDir returns the absolute path of the configuration root directory.
+// This is, in order of precedence: the directory specified with
+// SetConfigRoot, the directory $TODOGO_HOME, the directory
+// $XDG_CONFIG_HOME/galuma/todogo, and the directory $HOME/.config/galuma/todogo.
+func ConfigRootDir() string {
+	if cfgrootdir != "" {
+		return absPath(cfgrootdir)
+	}
+	if dirpath := os.Getenv(HomeEnvVariable); dirpath != "" {
+		return absPath(dirpath)
+	}
+	if dirpath := os.Getenv("XDG_CONFIG_HOME"); dirpath != "" {
+		return filepath.Join(absPath(dirpath), xdgDirname)
+	}
+	return filepath.Join(os.Getenv("HOME"), configDirname)
+}
+
+// DataRootDir returns the absolute path of the root directory of the contexts
+// workspaces (the directory relative context paths are relative to). This is
+// the configuration root directory, unless the XDG_DATA_HOME variable is
+// defined (and the configuration root is not specified explicitly), in which
+// case it is the directory $XDG_DATA_HOME/galuma/todogo. The workspaces
+// created by the previous versions in the configuration root directory are
+// still used if they exist (see hasLegacyWorkspaces).
+func DataRootDir() string {
+	cfgdirpath := ConfigRootDir()
+	if cfgrootdir != "" || os.Getenv(HomeEnvVariable) != "" {
+		return cfgdirpath
+	}
+	dirpath := os.Getenv("XDG_DATA_HOME")
+	if dirpath == "" {
+		return cfgdirpath
+	}
+	if hasLegacyWorkspaces(cfgdirpath) {
+		return cfgdirpath
+	}
+	return filepath.Join(absPath(dirpath), xdgDirname)
+}
+
+// hasLegacyWorkspaces returns true if a workspace of a context with a relative
+// path (the default context or a context of the configuration file) exists in
+// the configuration root directory cfgdirpath.
+func hasLegacyWorkspaces(cfgdirpath string) bool {
+	dirpaths := []string{defaultContextName}
+	var config Config
+	bytes, err := LoadBytes(filepath.Join(cfgdirpath, configFilename))
+	if err == nil && json.Unmarshal(bytes, &config) == nil {
+		for _, context := range config.ContextList {
+			if !filepath.IsAbs(context.DirPath) {
+				dirpaths = append(dirpaths, context.DirPath)
+			}
+		}
+	}
+	for _, dirpath := range dirpaths {
+		if exists, _ := PathExists(filepath.Join(cfgdirpath, dirpath)); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// absPath returns the absolute representation of the path (the path itself if
+// it can not be determined)
+func absPath(path string) string {
+	abspath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abspath
+}
+
 // DefaultContextPath returns a default path for this context name. The default
 // context workspace is a subdirectory of the configuration root folder with a
 // dirname equals to the context name.
@@ -55,6 +157,16 @@ type Parameters struct {
 	WithColor bool
 	// Indicators is the template of indicators of a task string representation
 	Indicators string
+	// WIPLimitTodo is the maximal number of todo tasks in the kanban view (0 for no limit)
+	WIPLimitTodo int
+	// WIPLimitDoing is the maximal number of doing tasks in the kanban view (0 for no limit)
+	WIPLimitDoing int
+	// WIPLimitDone is the maximal number of done tasks in the kanban view (0 for no limit)
+	WIPLimitDone int
+	// ReviewAge is the age (in days) from which a task is stale in the review (0 for the default age)
+	ReviewAge int
+	// AutoParentStatus indicates wether the status of the parent tasks is derived from the status of their children
+	AutoParentStatus bool
 }
 
 func (parameters Parameters) String() string {
@@ -72,6 +184,15 @@ type Config struct {
 	ContextList ContextArray
 	Parameters  Parameters
 	filepath    string // WRN: no jsonified (on purpose) because starts with minus letter
+	datadir     string // root directory of the contexts workspaces (see DataRootDir)
+
+	// activeContext overrides the context ContextName for this invocation
+	// (see discoverActiveContext), and activeOrigin explains why.
+	activeContext *Context
+	activeOrigin  string
+	// overrideError is the error of an invalid override, ignored for this
+	// invocation (see IgnoreInvalidContextOverride)
+	overrideError error
 }
 
 // defaultConfig() creates and returns a default configuration when no
@@ -91,6 +212,7 @@ func defaultConfig() Config {
 			PrettyPrint:    true,
 			WithColor:      true,
 			Indicators:     DefaultIndicatorsTemplate,
+			ReviewAge:      DefaultReviewAge,
 		},
 	}
 	return config
@@ -108,9 +230,19 @@ func (config *Config) Load(filepath string) error {
 		return err
 	}
 	config.filepath = filepath
+	config.setDataDir(config.datadir)
 	return nil
 }
 
+// setDataDir specifies the root directory of the contexts workspaces of this
+// configuration (default to DataRootDir if blank).
+func (config *Config) setDataDir(dirpath string) {
+	config.datadir = dirpath
+	for i := 0; i < len(config.ContextList); i++ {
+		config.ContextList[i].rootdir = dirpath
+	}
+}
+
 // SaveTo writes the Config data into a json file.
 // It implements the jsonable interface.
 func (config *Config) SaveTo(filepath string) error {
@@ -140,6 +272,7 @@ func (config *Config) Save() error {
 
 // AddContext adds a new context in the configuration
 func (config *Config) AddContext(context Context) error {
+	context.rootdir = config.datadir
 	return config.ContextList.append(context)
 }
 
@@ -158,8 +291,14 @@ func (config *Config) SetActiveContext(name string) error {
 	return nil
 }
 
-// GetActiveContext returns the currently active context
+// GetActiveContext returns the currently active context. This is the context
+// ContextName of the configuration, unless it is overridden for this
+// invocation by the environment variable TODO_CONTEXT or by a workspace marker
+// (see ActiveContextOrigin).
 func (config *Config) GetActiveContext() *Context {
+	if config.activeContext != nil {
+		return config.activeContext
+	}
 	return config.ContextList.getContext(config.ContextName)
 }
 
@@ -224,15 +363,28 @@ func (config Config) ContextsString() string {
 
 func (config Config) createContextsString(dotSymbol string, renderingFunc renderingFunction) string {
 	s := "\n"
+	active := config.GetActiveContext()
 	for i := 0; i < len(config.ContextList); i++ {
 		context := config.ContextList[i]
-		if context.Name == config.ContextName {
+		if active != nil && context == *active {
 			s += renderingFunc(fmt.Sprintf("%s %s\n", dotSymbol, context.String()))
 		} else {
 			s += fmt.Sprintf("  %s\n", context.String())
 		}
 	}
+	if active != nil && config.ContextList.index(func(c Context) bool { return c == *active }) == ctxUndefIndex {
+		// The active context is a local workspace, not registered in the
+		// configuration
+		s += renderingFunc(fmt.Sprintf("%s %s\n", dotSymbol, active.String()))
+	}
 	s += fmt.Sprintf("\nLegend: %s\n", renderingFunc(dotSymbol+" active context"))
+	if origin := config.ActiveContextOrigin(); origin != "" {
+		s += fmt.Sprintf("\nWRN: the active context is overridden by the %s\n", origin)
+	}
+	if config.overrideError != nil {
+		message := strings.TrimPrefix(config.overrideError.Error(), "ERR: ")
+		s += fmt.Sprintf("\nWRN: the override of the active context is ignored: %s\n", message)
+	}
 	return s
 }
 
@@ -243,8 +395,9 @@ func (config Config) InfoString() string {
 	// Configuration files
 	s += "Configuration files:\n"
 	s += "-------------------\n\n"
-	s += fmt.Sprintf(" Configuration root directory: %s\n", cfgdirpath)
-	s += fmt.Sprintf(" Configuration file path     : %s\n", cfgfilepath)
+	s += fmt.Sprintf(" Configuration root directory: %s\n", filepath.Dir(config.File()))
+	s += fmt.Sprintf(" Configuration file path     : %s\n", config.File())
+	s += fmt.Sprintf(" Contexts root directory     : %s\n", config.dataDir())
 	s += "\n"
 
 	// Configuration Parameters
@@ -259,27 +412,43 @@ func (config Config) InfoString() string {
 	s += config.createContextsString(dotSymbolMap[false], renderingFunctionMap[false])
 	s += "\n"
 
-	s += "!! NOTE: apart form the management of the contexts (create, remove, select),\n"
-	s += "!! there is no todo command to edit the configuration (up to now). If you need\n"
-	s += "!! to modify the configuration, you should directly edit the configuration file.\n"
+	s += "!! NOTE: the configuration parameters can be modified with the command:\n"
+	s += "!! todo config set <key> <value> (get <key> to read, unset <key> to reset)\n"
 	return s
 }
 
 // =========================================================================
 
-// GetConfig returns the current configuration (and load it if first call)
-func GetConfig() (*Config, error) {
-	if userConfig != nil {
-		return userConfig, nil
+// dataDir returns the root directory of the contexts workspaces
+func (config Config) dataDir() string {
+	if config.datadir != "" {
+		return config.datadir
 	}
+	return DataRootDir()
+}
+
+// LoadConfig loads the configuration whose root directory is rootdir, i.e. the
+// configuration file rootdir/config.json. The relative context paths are
+// relative to rootdir. A default configuration is created if the file does not
+// exist. Contrary to GetConfig, this function does not rely on the global
+// state (environment variables and current configuration).
+func LoadConfig(rootdir string) (*Config, error) {
+	return loadConfig(filepath.Join(absPath(rootdir), configFilename), absPath(rootdir))
+}
+
+// loadConfig loads the configuration file cfgfilepath (or creates a default
+// configuration if it does not exist), with datadir as contexts root directory.
+func loadConfig(cfgfilepath string, datadir string) (*Config, error) {
 	exists, err := PathExists(cfgfilepath)
 	if exists && err != nil {
 		return nil, err
 	}
 
 	var config Config
+	config.datadir = datadir
 	if !exists {
 		config = defaultConfig()
+		config.setDataDir(datadir)
 		config.SaveTo(cfgfilepath)
 	} else {
 		err = config.Load(cfgfilepath)
@@ -287,7 +456,32 @@ func GetConfig() (*Config, error) {
 			return nil, err
 		}
 	}
-	userConfig = &config
+	return &config, nil
+}
+
+// GetConfig returns the current configuration (and load it if first call). The
+// configuration is loaded from the root directory ConfigRootDir, and the active
+// context could be overridden by the environment variable TODO_CONTEXT or by a
+// workspace marker found from the current directory (an invalid override is an
+// error, unless it is ignored, see IgnoreInvalidContextOverride).
+func GetConfig() (*Config, error) {
+	if userConfig != nil {
+		return userConfig, nil
+	}
+	config, err := loadConfig(filepath.Join(ConfigRootDir(), configFilename), DataRootDir())
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err == nil {
+		err = config.discoverActiveContext(cwd)
+		if err != nil && !ignoreInvalidOverride {
+			return nil, err
+		}
+		config.overrideError = err
+	}
+	userConfig = config
 	return userConfig, nil
 }
 
@@ -2,9 +2,25 @@ package todo
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
+// TestMain runs the tests with a temporary configuration root directory, so
+// that the tests never use the user configuration.
+func TestMain(m *testing.M) {
+	rootdir, err := os.MkdirTemp("", "todogo")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	SetConfigRoot(rootdir)
+	code := m.Run()
+	os.RemoveAll(rootdir)
+	os.Exit(code)
+}
+
 func TestContexts(t *testing.T) {
 	contexts := ContextArray{
 		Context{DirPath: "/tmp/titi", Name: "titi"},
@@ -91,3 +107,122 @@ func TestConfigIO(t *testing.T) {
 	}
 
 }
+
+func TestConfigLoadJournals(t *testing.T) {
+	rootdir := t.TempDir()
+	config := Config{
+		ContextName: "work",
+		ContextList: ContextArray{
+			Context{DirPath: filepath.Join(rootdir, "work"), Name: "work"},
+			Context{DirPath: filepath.Join(rootdir, "home"), Name: "home"},
+		},
+	}
+	journal := CreateTestJournal()
+	journal.SaveTo(config.GetContext("work").JournalPath())
+
+	journals, err := config.LoadJournals(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 2 || journals[0].Context.Name != "home" {
+		t.Errorf("The journals are not sorted by context name")
+	}
+	if len(journals[1].Journal.TaskList) != len(journal.TaskList) {
+		t.Errorf("Nb tasks is %d (should be %d)", len(journals[1].Journal.TaskList), len(journal.TaskList))
+	}
+	label := journals[1].label(journals[1].Journal.TaskList[0])
+	if label != "work:1" {
+		t.Errorf("label is %s (should be %s)", label, "work:1")
+	}
+
+	_, err = config.LoadJournals([]string{"work", "nope"})
+	if err == nil {
+		t.Error("The context nope does not exist")
+	}
+}
+
+func TestConfigRootDir(t *testing.T) {
+	defer SetConfigRoot(cfgrootdir)
+
+	SetConfigRoot("")
+	t.Setenv("HOME", "/home/user")
+	t.Setenv(HomeEnvVariable, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	if dirpath := ConfigRootDir(); dirpath != "/home/user/.config/galuma/todogo" {
+		t.Errorf("root dir is %s (should be %s)", dirpath, "/home/user/.config/galuma/todogo")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	if dirpath := ConfigRootDir(); dirpath != "/xdg/config/galuma/todogo" {
+		t.Errorf("root dir is %s (should be %s)", dirpath, "/xdg/config/galuma/todogo")
+	}
+	if dirpath := DataRootDir(); dirpath != "/xdg/data/galuma/todogo" {
+		t.Errorf("data dir is %s (should be %s)", dirpath, "/xdg/data/galuma/todogo")
+	}
+
+	t.Setenv(HomeEnvVariable, "/todogo")
+	if dirpath := DataRootDir(); dirpath != "/todogo" {
+		t.Errorf("data dir is %s (should be %s)", dirpath, "/todogo")
+	}
+
+	SetConfigRoot("/explicit")
+	if dirpath := ConfigRootDir(); dirpath != "/explicit" {
+		t.Errorf("root dir is %s (should be %s)", dirpath, "/explicit")
+	}
+}
+
+func TestDataRootDirLegacy(t *testing.T) {
+	defer SetConfigRoot(cfgrootdir)
+
+	SetConfigRoot("")
+	xdgdir := t.TempDir()
+	t.Setenv(HomeEnvVariable, "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(xdgdir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(xdgdir, "data"))
+	cfgdirpath := ConfigRootDir()
+	datadirpath := filepath.Join(xdgdir, "data", xdgDirname)
+
+	// A configuration with no default context, but a relative context work
+	config := Config{ContextName: "work", ContextList: ContextArray{Context{DirPath: "work", Name: "work"}}}
+	err := config.SaveTo(filepath.Join(cfgdirpath, configFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirpath := DataRootDir(); dirpath != datadirpath {
+		t.Errorf("data dir is %s (should be %s)", dirpath, datadirpath)
+	}
+
+	// The workspace of the context work was created by a previous version
+	os.MkdirAll(filepath.Join(cfgdirpath, "work"), os.ModePerm)
+	if dirpath := DataRootDir(); dirpath != cfgdirpath {
+		t.Errorf("data dir is %s (should be %s)", dirpath, cfgdirpath)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	rootdir := t.TempDir()
+	config, err := LoadConfig(rootdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.File() != filepath.Join(rootdir, configFilename) {
+		t.Errorf("config file is %s (should be in %s)", config.File(), rootdir)
+	}
+	journalpath := config.GetActiveContext().JournalPath()
+	if journalpath != filepath.Join(rootdir, defaultContextName, JournalFilename) {
+		t.Errorf("journal path is %s (should be in %s)", journalpath, rootdir)
+	}
+
+	config.AddContext(Context{DirPath: "work", Name: "work"})
+	config.Save()
+	config, err = LoadConfig(rootdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirpath := config.GetContext("work").absDirPath()
+	if dirpath != filepath.Join(rootdir, "work") {
+		t.Errorf("context path is %s (should be %s)", dirpath, filepath.Join(rootdir, "work"))
+	}
+}
@@ -25,6 +25,7 @@ const (
 type Context struct {
 	DirPath string
 	Name    string
+	rootdir string // root directory of relative DirPath (see Config.setDataDir)
 }
 
 // String implements the stringable interface for a Context
@@ -34,15 +35,19 @@ func (context Context) String() string {
 
 // absDirPath returns the absolute path to the root directory of this context.
 // If DirPath is a relative path, then it is considered as relative to the
-// configuration root directory. That is the case generally when the option
-// -p was not specified at context creation. In such a case, the context
-// workspace is created as a subdirectory of the configuration directory
-// with name equal to the context name.
+// contexts root directory (see DataRootDir). That is the case generally when
+// the option -p was not specified at context creation. In such a case, the
+// context workspace is created as a subdirectory of the contexts root
+// directory with name equal to the context name.
 func (context Context) absDirPath() string {
 	if filepath.IsAbs(context.DirPath) {
 		return context.DirPath
 	}
-	return filepath.Join(cfgdirpath, context.DirPath)
+	rootdir := context.rootdir
+	if rootdir == "" {
+		rootdir = DataRootDir()
+	}
+	return filepath.Join(rootdir, context.DirPath)
 }
 
 // JournalPath returns the absolute path of the journal of this context
@@ -0,0 +1,292 @@
+package todo
+
+// Implementation of the CSV format (one task per row, with a header row that
+// gives the names of the columns)
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	csvLayoutDate     = layoutISO
+	csvLayoutDateTime = "2006-01-02 15:04:05"
+	csvBoardYes       = "yes"
+)
+
+// DefaultCSVColumns is the default list of the columns of a CSV export
+var DefaultCSVColumns = []string{"uid", "gid", "status", "created", "parent", "board", "description"}
+
+// csvColumn defines how a column of a CSV file is written from a task (get)
+// and read into a task (set).
+type csvColumn struct {
+	get func(journal TaskJournal, task Task) string
+	set func(task *Task, value string, mapping CSVMapping) error
+}
+
+// csvDate returns the CSV representation of a date (blank if undefined)
+func csvDate(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).Format(csvLayoutDate)
+}
+
+var csvColumns = map[string]csvColumn{
+	"uid": {
+		get: func(journal TaskJournal, task Task) string { return fmt.Sprintf("%d", task.UIndex) },
+		set: func(task *Task, value string, mapping CSVMapping) (err error) {
+			task.UIndex, err = parseUID(value)
+			return err
+		},
+	},
+	"gid": {
+		get: func(journal TaskJournal, task Task) string { return task.globalID() },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			if !task.setGlobalID(value) {
+				return fmt.Errorf("ERR: %s is not a valid global identifier", value)
+			}
+			return nil
+		},
+	},
+	"status": {
+		get: func(journal TaskJournal, task Task) string { return task.Status.Label() },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			if label, exists := mapping.Status[value]; exists {
+				value = label
+			}
+			return task.Status.Value(strings.ToLower(value))
+		},
+	},
+	"created": {
+		get: func(journal TaskJournal, task Task) string { return csvDate(task.Timestamp) },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			date, err := mapping.parseDate(value)
+			if err == nil && date != 0 {
+				task.Timestamp = date
+			}
+			return err
+		},
+	},
+	"completed": {
+		get: func(journal TaskJournal, task Task) string { return csvDate(task.CompletionDate()) },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			date, err := mapping.parseDate(value)
+			if err == nil && date != 0 {
+				task.History = append(task.History, StatusChange{Status: StatusDone, Timestamp: date})
+			}
+			return err
+		},
+	},
+	"due": {
+		get: func(journal TaskJournal, task Task) string { return csvDate(task.Due) },
+		set: func(task *Task, value string, mapping CSVMapping) (err error) {
+			task.Due, err = mapping.parseDate(value)
+			return err
+		},
+	},
+	"parent": {
+		get: func(journal TaskJournal, task Task) string { return fmt.Sprintf("%d", task.ParentID) },
+		set: func(task *Task, value string, mapping CSVMapping) (err error) {
+			task.ParentID, err = parseUID(value)
+			return err
+		},
+	},
+	"board": {
+		get: func(journal TaskJournal, task Task) string {
+			if task.OnBoard {
+				return csvBoardYes
+			}
+			return ""
+		},
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			value = strings.ToLower(value)
+			task.OnBoard = value == csvBoardYes || value == "true" || value == "x"
+			return nil
+		},
+	},
+	"priority": {
+		get: func(journal TaskJournal, task Task) string { return task.Priority },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			task.Priority = strings.ToUpper(value)
+			return nil
+		},
+	},
+	"description": {
+		get: func(journal TaskJournal, task Task) string { return task.Description },
+		set: func(task *Task, value string, mapping CSVMapping) error {
+			task.Description = value
+			return nil
+		},
+	},
+	"note": {
+		get: func(journal TaskJournal, task Task) string {
+			body, _ := journal.GetNoteContent(task.UIndex)
+			return body
+		},
+		// The note is read by ParseCSV (it is not a task attribute)
+		set: func(task *Task, value string, mapping CSVMapping) error { return nil },
+	},
+}
+
+// CSVColumnNames returns the list of the names of the possible CSV columns
+func CSVColumnNames() []string {
+	names := append([]string{}, DefaultCSVColumns...)
+	return append(names, "completed", "due", "priority", "note")
+}
+
+// checkCSVColumns returns an error if one of the columns is not defined
+func checkCSVColumns(columns []string) error {
+	for _, column := range columns {
+		if _, exists := csvColumns[column]; !exists {
+			return fmt.Errorf("ERR: the column %s is not defined (should be in: %s)", column, CSVColumnNames())
+		}
+	}
+	return nil
+}
+
+// CSV returns the CSV representation of the tasks of this journal that satisfy
+// the given filter, with the specified columns (DefaultCSVColumns if empty).
+func (journal TaskJournal) CSV(taskFilter TaskFilter, columns []string) (string, error) {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	err := checkCSVColumns(columns)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+	writer.Write(columns)
+	for _, task := range journal.TaskList {
+		if !taskFilter(task) {
+			continue
+		}
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = csvColumns[column].get(journal, task)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return builder.String(), writer.Error()
+}
+
+// CSVMapping specifies how to read a foreign CSV file: Columns maps the todo
+// column names to the headers of the file, Status maps the values of the
+// status column to the todo status labels, and DateLayout is the layout (as
+// defined by the go time package) of the dates of the file.
+type CSVMapping struct {
+	Columns    map[string]string
+	Status     map[string]string
+	DateLayout string
+}
+
+// LoadCSVMapping reads a CSVMapping from a json file
+func LoadCSVMapping(filepath string) (CSVMapping, error) {
+	var mapping CSVMapping
+	bytes, err := LoadBytes(filepath)
+	if err != nil {
+		return mapping, err
+	}
+	err = json.Unmarshal(bytes, &mapping)
+	if err != nil {
+		return mapping, err
+	}
+	columns := make([]string, 0, len(mapping.Columns))
+	for column := range mapping.Columns {
+		columns = append(columns, column)
+	}
+	return mapping, checkCSVColumns(columns)
+}
+
+// parseDate parses a date of the CSV file (0 if blank)
+func (mapping CSVMapping) parseDate(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	layouts := []string{csvLayoutDateTime, csvLayoutDate}
+	if mapping.DateLayout != "" {
+		layouts = []string{mapping.DateLayout}
+	}
+	for _, layout := range layouts {
+		date, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return date.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("ERR: the date %s is not valid", value)
+}
+
+// ParseCSV parses the CSV text and returns the list of tasks and the contents
+// of their notes. The first row is the header, whose names are the todo column
+// names (see CSVColumnNames) or the foreign names specified by the mapping.
+// The columns that are not mapped are ignored. The UIndex of the tasks are the
+// values of the uid column (the row numbers if there is no uid column), to be
+// remapped with TaskJournal.ImportWithNotes.
+func ParseCSV(text string, mapping CSVMapping) (TaskArray, map[TaskID]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return TaskArray{}, nil, nil
+	}
+
+	// Index of the todo columns in the records
+	headers := make(map[string]int)
+	for i, header := range records[0] {
+		headers[strings.TrimSpace(header)] = i
+	}
+	indeces := make(map[string]int)
+	for column := range csvColumns {
+		header := column
+		if foreign, exists := mapping.Columns[column]; exists {
+			header = foreign
+		}
+		if idx, exists := headers[header]; exists {
+			indeces[column] = idx
+		}
+	}
+	if _, exists := indeces["description"]; !exists {
+		return nil, nil, fmt.Errorf("ERR: the CSV file has no description column")
+	}
+	_, withUID := indeces["uid"]
+
+	tasks := make(TaskArray, 0, len(records)-1)
+	notes := make(map[TaskID]string)
+	for nrow, record := range records[1:] {
+		task := Task{
+			UIndex:    TaskID(nrow + 1),
+			Timestamp: timestamp(),
+			Status:    StatusTodo,
+		}
+		for column, idx := range indeces {
+			if idx >= len(record) || (column == "parent" && !withUID) {
+				continue
+			}
+			value := strings.TrimSpace(record[idx])
+			if value == "" {
+				continue
+			}
+			err = csvColumns[column].set(&task, value, mapping)
+			if err != nil {
+				return nil, nil, fmt.Errorf("ERR: row %d: column %s: %s", nrow+2, column, err)
+			}
+		}
+		if tasks.indexFromUID(task.UIndex) != noIndex || task.UIndex == NoUID {
+			return nil, nil, fmt.Errorf("ERR: row %d: the uid %d is not valid or already used", nrow+2, task.UIndex)
+		}
+		if idx, exists := indeces["note"]; exists && idx < len(record) {
+			notes[task.UIndex] = record[idx]
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, notes, nil
+}
@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	journal := CreateTestJournal()
+	ptask, _ := journal.GetTask(2)
+	ptask.ParentID = 1
+	ptask.Description = "Description, with a comma"
+	ptask.OnBoard = true
+	ptask, _ = journal.GetTask(3)
+	ptask.NextStatus()
+
+	_, err := journal.CSV(TaskFilterAll, []string{"uid", "undefined"})
+	if err == nil {
+		t.Error("The column undefined should be rejected")
+	}
+
+	text, err := journal.CSV(TaskFilterAll, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	printlog(text)
+
+	tasks, _, err := ParseCSV(text, CSVMapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(journal.TaskList) {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), len(journal.TaskList))
+	}
+	for i, task := range tasks {
+		init := journal.TaskList[i]
+		if task.UIndex != init.UIndex || task.ParentID != init.ParentID || task.GUID != init.GUID {
+			t.Errorf("Task %d is not preserved (parent %d, should be %d)", init.UIndex, task.ParentID, init.ParentID)
+		}
+		if task.Description != init.Description || task.Status != init.Status || task.OnBoard != init.OnBoard {
+			t.Errorf("Description is %s (should be %s)", task.Description, init.Description)
+		}
+	}
+}
+
+func TestParseCSVWithMapping(t *testing.T) {
+	text := `Title,State,Opened,Owner
+Fix the roof,Open,01/02/2024,Bob
+Paint the wall,Closed,03/02/2024,Alice
+`
+	mapping := CSVMapping{
+		Columns:    map[string]string{"description": "Title", "status": "State", "created": "Opened"},
+		Status:     map[string]string{"Open": "todo", "Closed": "done"},
+		DateLayout: "02/01/2006",
+	}
+	tasks, _, err := ParseCSV(text, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 2)
+	}
+	if tasks[0].Description != "Fix the roof" || tasks[0].Status != StatusTodo {
+		t.Errorf("Description is %s (should be %s)", tasks[0].Description, "Fix the roof")
+	}
+	if tasks[1].Status != StatusDone || datelabel(tasks[1].Timestamp) != "2024-Feb-03" {
+		t.Errorf("Date is %s (should be %s)", datelabel(tasks[1].Timestamp), "2024-Feb-03")
+	}
+
+	_, _, err = ParseCSV("Title\nFix the roof\n", CSVMapping{})
+	if err == nil {
+		t.Error("A CSV file with no description column should be rejected")
+	}
+
+	// The short IDs are not valid task indeces in a file
+	_, _, err = ParseCSV("uid,description\nt1,Fix the roof\n", CSVMapping{})
+	if err == nil {
+		t.Error("The uid t1 is not a valid task index")
+	}
+}
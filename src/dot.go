@@ -0,0 +1,94 @@
+package todo
+
+// Implementation of the Graphviz DOT format, to render the tree of the tasks
+// as a diagram, e.g.:
+//
+//   $ todo export -format dot | dot -Tsvg -o roadmap.svg
+//
+// The tasks are the nodes of the graph, filled with the color of their status
+// (the same colors as in the terminal rendering), and the edges go from the
+// parent tasks to their children. The tasks on board are highlighted with a
+// bold border.
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dotLabelWidth  = 160.0 // width of the labels of the nodes (in points)
+	dotBoardBorder = 3     // width of the border of the tasks on board
+)
+
+// hex returns the hexadecimal representation of the color (#rrggbb)
+func (color pdfColor) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(255*color.r), int(255*color.g), int(255*color.b))
+}
+
+// lighten returns the color mixed with white (ratio between 0 and 1)
+func (color pdfColor) lighten(ratio float64) pdfColor {
+	return pdfColor{
+		r: color.r + (1-color.r)*ratio,
+		g: color.g + (1-color.g)*ratio,
+		b: color.b + (1-color.b)*ratio,
+	}
+}
+
+// dotString returns the DOT quoted string of the text
+func dotString(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
+
+// dotNode returns the name of the node of the task
+func dotNode(uindex TaskID) string {
+	return fmt.Sprintf("t%d", uindex)
+}
+
+// DOT returns the Graphviz DOT representation of the tree of the tasks of this
+// journal that satisfy the given filter (an edge is drawn only if both the
+// parent and the child satisfy the filter). If title is not blank, it is used
+// as the label of the graph.
+func (journal TaskJournal) DOT(title string, taskFilter TaskFilter) string {
+	tree := make(treeMap, 0)
+	tree.initialize(journal.TaskList)
+
+	s := "digraph todo {\n"
+	if title != "" {
+		s += fmt.Sprintf("  label=%s;\n  labelloc=t;\n", dotString(title))
+	}
+	s += "  rankdir=LR;\n"
+	s += "  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n"
+	s += "  edge [color=\"#888888\"];\n"
+
+	for _, task := range journal.TaskList {
+		if !taskFilter(task) {
+			continue
+		}
+		lines := wrapText(task.Description, pdfRegular, 10, dotLabelWidth)
+		label := fmt.Sprintf("%d - %s", task.UIndex, strings.Join(lines, "\n"))
+		color := pdfColors[taskStatusColors[task.Status]]
+		attributes := fmt.Sprintf("label=%s, color=%s, fillcolor=%s", dotString(label),
+			dotString(color.hex()), dotString(color.lighten(0.8).hex()))
+		if task.OnBoard {
+			attributes += fmt.Sprintf(", penwidth=%d", dotBoardBorder)
+		}
+		if task.Status == StatusDone {
+			attributes += ", fontcolor=" + dotString(pdfGrey.hex())
+		}
+		s += fmt.Sprintf("  %s [%s];\n", dotNode(task.UIndex), attributes)
+	}
+
+	for _, task := range journal.TaskList {
+		if !taskFilter(task) {
+			continue
+		}
+		for _, childID := range tree[task.UIndex] {
+			child, err := journal.GetTask(childID)
+			if err == nil && taskFilter(*child) {
+				s += fmt.Sprintf("  %s -> %s;\n", dotNode(task.UIndex), dotNode(childID))
+			}
+		}
+	}
+	s += "}\n"
+	return s
+}
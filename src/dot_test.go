@@ -0,0 +1,59 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	tasks := createTreeTaskArray()
+	journal := TaskJournal{TaskList: tasks}
+	ptask, _ := journal.GetTask(12)
+	ptask.Description = `B.2.2 "quoted"`
+	ptask.OnBoard = true
+
+	graph := journal.DOT("Tree", TaskFilterAll)
+	printlog(graph)
+
+	if !strings.HasPrefix(graph, "digraph todo {") || !strings.HasSuffix(graph, "}\n") {
+		t.Error("The graph is not a valid digraph")
+	}
+	// One edge for each task with a parent
+	nedges := 0
+	for _, task := range tasks {
+		if task.ParentID != NoUID {
+			nedges++
+		}
+	}
+	if strings.Count(graph, " -> ") != nedges {
+		t.Errorf("Nb edges is %d (should be %d)", strings.Count(graph, " -> "), nedges)
+	}
+	if !strings.Contains(graph, `t12 -> t13;`) {
+		t.Error("The edge from 12 to 13 is missing")
+	}
+	if !strings.Contains(graph, `B.2.2 \"quoted\"`) {
+		t.Error("The description should be escaped")
+	}
+	if strings.Count(graph, "penwidth=") != 1 {
+		t.Errorf("Nb tasks on board is %d (should be %d)", strings.Count(graph, "penwidth="), 1)
+	}
+}
+
+func TestSubtreeFilter(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	filter, err := journal.SubtreeFilter(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := journal.DOT("", filter)
+	printlog(graph)
+	if strings.Count(graph, "label=") != 2 {
+		t.Errorf("Nb nodes is %d (should be %d)", strings.Count(graph, "label="), 2)
+	}
+	if strings.Count(graph, " -> ") != 1 {
+		t.Errorf("Nb edges is %d (should be %d)", strings.Count(graph, " -> "), 1)
+	}
+	if _, err := journal.SubtreeFilter(999); err == nil {
+		t.Error("The filter of a task that does not exist should fail")
+	}
+}
@@ -37,6 +37,16 @@ func WriteBytes(fpath string, bytes []byte) error {
 	return nil
 }
 
+// CopyFile copies the content of the file srcpath to the file dstpath. The
+// directory of dstpath is created if it does not exist.
+func CopyFile(srcpath string, dstpath string) error {
+	bytes, err := LoadBytes(srcpath)
+	if err != nil {
+		return err
+	}
+	return WriteBytes(dstpath, bytes)
+}
+
 // PathExists returns true if the path exists
 func PathExists(path string) (bool, error) {
 	_, err := os.Stat(path)
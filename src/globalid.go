@@ -0,0 +1,233 @@
+package todo
+
+// Implementation of the global identifiers of the tasks. The global identifier
+// (GUID) of a task is a ULID: 48 bits of the creation date (in milliseconds)
+// followed by 80 random bits, written with 26 characters of the Crockford's
+// base32 alphabet. Then two tasks created at the same time with the same text
+// have different identifiers, and the identifiers of the tasks created with
+// New can be sorted by creation (the tasks created in the same millisecond are
+// in random order). The tasks imported or migrated only have the date of the
+// task, with the precision of the second. The GUID is the only stable identity
+// of a task: it identifies the task in the exchange formats (see globalID),
+// the relations with the tasks of another journal (see TaskJournal.Move), and
+// the note file of a task is named after its GUID.
+//
+// The GIndex (GID) is a compact numerical alias of the task, used as the UID
+// of the archived tasks. It is derived from the GUID and the date of the task,
+// and made unique in its context only (the journal and its archive, see
+// Reserve) when the task is added: it is not an identity, and it can change
+// when the task is moved to another context. The UID of a task archived before
+// the migration of its GIndex (see MigrateGlobalIDs) can differ from its
+// GIndex.
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// crockfordAlphabet is the base32 alphabet of the ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newGUID returns a new ULID whose date is the given date
+func newGUID(date time.Time) string {
+	var bits [16]byte
+	ms := uint64(date.UnixNano() / int64(time.Millisecond))
+	for i := 0; i < 6; i++ {
+		bits[i] = byte(ms >> (8 * (5 - i)))
+	}
+	_, err := rand.Read(bits[6:])
+	if err != nil {
+		panic(err)
+	}
+	return encodeGUID(bits)
+}
+
+// encodeGUID returns the ULID representation of the 128 bits
+func encodeGUID(bits [16]byte) string {
+	// The 128 bits are written in 26 groups of 5 bits (the first group has
+	// only 3 significant bits), from the highest to the lowest bits
+	guid := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		guid[i] = crockfordAlphabet[bits[15]&0x1f]
+		// Shift the 128 bits by 5 bits to the right
+		for j := 15; j > 0; j-- {
+			bits[j] = bits[j]>>5 | bits[j-1]<<3
+		}
+		bits[0] >>= 5
+	}
+	return string(guid)
+}
+
+// decodeGUID returns the 128 bits of the ULID guid. Returns false if guid is
+// not a valid ULID.
+func decodeGUID(guid string) ([16]byte, bool) {
+	var bits [16]byte
+	guid = strings.ToUpper(guid)
+	if len(guid) != 26 || guid[0] > '7' {
+		return bits, false
+	}
+	for i := 0; i < len(guid); i++ {
+		value := strings.IndexByte(crockfordAlphabet, guid[i])
+		if value < 0 {
+			return bits, false
+		}
+		// Shift the 128 bits by 5 bits to the left
+		for j := 0; j < 15; j++ {
+			bits[j] = bits[j]<<5 | bits[j+1]>>3
+		}
+		bits[15] = bits[15]<<5 | byte(value)
+	}
+	return bits, true
+}
+
+// globalID returns the identifier of this task in the exchange formats: its
+// GUID, or its GIndex if it has no GUID (a task created by a former version of
+// todo, see MigrateGlobalIDs). The GIndex is only a reference inside the
+// exported file, it is not read back as an identity (see setGlobalID).
+func (task Task) globalID() string {
+	if task.GUID == "" {
+		return fmt.Sprintf("%d", task.GIndex)
+	}
+	return task.GUID
+}
+
+// setGlobalID sets the GUID of this task from the value written by globalID.
+// A GIndex (an integer, written for the tasks with no GUID) is valid but
+// ignored, the task being given new identifiers when imported. Returns false
+// if the value is neither a GUID nor a GIndex.
+func (task *Task) setGlobalID(value string) bool {
+	if _, ok := decodeGUID(value); ok {
+		task.GUID = strings.ToUpper(value)
+		return true
+	}
+	_, err := strconv.ParseUint(value, 10, 64)
+	return err == nil
+}
+
+// noteBasename returns the basename of the note file of this task
+func (task Task) noteBasename() string {
+	if task.GUID == "" {
+		// Task created by a former version of todo (see MigrateGlobalIDs)
+		return fmt.Sprintf("%d.rst", task.GIndex)
+	}
+	return task.GUID + ".rst"
+}
+
+// Reserve makes the global identifiers of the tasks of the other journal (the
+// archive of this journal) unavailable for the tasks added to this journal
+// with New or Import, so that the GIndex of a task is unique in its context and
+// the task can be archived with its GIndex as UID.
+func (journal *TaskJournal) Reserve(other TaskJournal) {
+	journal.reserved = append(journal.reserved, other.TaskList...)
+}
+
+// gindexUsed returns true if the GIndex is used by a task of this journal or
+// reserved (see reservedGIndex).
+func (journal TaskJournal) gindexUsed(gindex TaskID) bool {
+	used := func(task Task) bool { return task.GIndex == gindex }
+	return journal.TaskList.index(used) != noIndex || journal.reservedGIndex(gindex)
+}
+
+// reservedGIndex returns true if the GIndex is reserved, as GIndex or UID of
+// an archived task (see Reserve).
+func (journal TaskJournal) reservedGIndex(gindex TaskID) bool {
+	archived := func(task Task) bool { return task.GIndex == gindex || task.UIndex == gindex }
+	return journal.reserved.index(archived) != noIndex
+}
+
+// guidUsed returns true if the GUID is used by a task of this journal or
+// reserved (see Reserve).
+func (journal TaskJournal) guidUsed(guid string) bool {
+	used := func(task Task) bool { return task.GUID == guid }
+	return guid != "" && (journal.TaskList.index(used) != noIndex || journal.reserved.index(used) != noIndex)
+}
+
+// initGlobalIndex initialises the global identifiers of the task, so that they
+// are not used by another task of this journal or reserved. The GUID of the
+// task is kept if it is not used (e.g. a task moved from another context).
+func (journal TaskJournal) initGlobalIndex(task *Task) {
+	if journal.guidUsed(task.GUID) {
+		task.GUID = ""
+	}
+	task.initGlobalIndex()
+	for journal.gindexUsed(task.GIndex) {
+		task.GIndex++
+	}
+}
+
+// UnmigratedGlobalIDs returns the UIDs of the tasks of this journal that have
+// no GUID (the tasks created by the former versions of todo) or whose GIndex
+// is used by a previous task or reserved (see Reserve).
+func (journal TaskJournal) UnmigratedGlobalIDs() TaskIDArray {
+	gindeces := make(map[TaskID]bool)
+	uindeces := make(TaskIDArray, 0)
+	for _, task := range journal.TaskList {
+		if task.GUID == "" || gindeces[task.GIndex] || journal.reservedGIndex(task.GIndex) {
+			uindeces = append(uindeces, task.UIndex)
+		}
+		gindeces[task.GIndex] = true
+	}
+	return uindeces
+}
+
+// MigrateGlobalIDs migrates the tasks returned by UnmigratedGlobalIDs. The
+// tasks with no GUID are given a GUID, and their note files named after the
+// GIndex (notebook/<GID>.rst) are copied to files named after the GUID (a note
+// shared by two tasks with the same GIndex is copied for each task). The
+// GIndex values are kept (they can be referred by the files exported by the
+// former versions), except the values used by a previous task or reserved,
+// that are incremented up to a free value. Returns the number of migrated
+// tasks and the paths of the former note files, that should be removed by the
+// caller once the journal is saved (the journal should not be saved if an
+// error is returned).
+func (journal *TaskJournal) MigrateGlobalIDs() (int, []string, error) {
+	unmigrated := make(map[TaskID]bool)
+	for _, uindex := range journal.UnmigratedGlobalIDs() {
+		unmigrated[uindex] = true
+	}
+	oldpaths := make([]string, 0)
+	copied := make(map[string]bool)
+	for i := 0; i < len(journal.TaskList); i++ {
+		task := &journal.TaskList[i]
+		if !unmigrated[task.UIndex] {
+			continue
+		}
+		oldNotePath := filepath.Join(NotebookDirname, task.noteBasename())
+		previous := func(candidate Task) bool { return candidate.GIndex == task.GIndex }
+		if journal.TaskList[:i].index(previous) != noIndex || journal.reservedGIndex(task.GIndex) {
+			gindex := task.GIndex + 1
+			for journal.gindexUsed(gindex) {
+				gindex++
+			}
+			task.GIndex = gindex
+		}
+		if task.GUID != "" {
+			continue
+		}
+
+		task.GUID = newGUID(time.Unix(task.Timestamp, 0))
+		if task.NotePath != oldNotePath {
+			// No note, or a note that is not named after the GIndex
+			continue
+		}
+		srcpath := journal.absNotePath(*task)
+		if exists, _ := PathExists(srcpath); !exists {
+			continue
+		}
+		notepath := filepath.Join(NotebookDirname, task.noteBasename())
+		err := CopyFile(srcpath, filepath.Join(filepath.Dir(journal.File()), notepath))
+		if err != nil {
+			return len(unmigrated), oldpaths, err
+		}
+		task.NotePath = notepath
+		if !copied[srcpath] {
+			copied[srcpath] = true
+			oldpaths = append(oldpaths, srcpath)
+		}
+	}
+	return len(unmigrated), oldpaths, nil
+}
@@ -0,0 +1,163 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGUID(t *testing.T) {
+	date := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	guid := newGUID(date)
+	if len(guid) != 26 {
+		t.Errorf("The length of %s is %d (should be %d)", guid, len(guid), 26)
+	}
+	for _, c := range guid {
+		if !strings.ContainsRune(crockfordAlphabet, c) {
+			t.Errorf("The character %c of %s is not in the alphabet", c, guid)
+		}
+	}
+	// Same date: same prefix (the date), different random part
+	other := newGUID(date)
+	if other[:10] != guid[:10] || other == guid {
+		t.Errorf("The GUIDs %s and %s should differ only by their random part", guid, other)
+	}
+	// The GUIDs are sorted by date
+	if later := newGUID(date.Add(time.Millisecond)); later <= guid {
+		t.Errorf("The GUID %s should be after %s", later, guid)
+	}
+
+	bits, ok := decodeGUID(strings.ToLower(guid))
+	if !ok || encodeGUID(bits) != guid {
+		t.Errorf("The GUID %s is not read back (%s)", guid, encodeGUID(bits))
+	}
+	for _, value := range []string{"", "8" + guid[1:], guid[:25] + "U", guid + "0"} {
+		if _, ok = decodeGUID(value); ok {
+			t.Errorf("The value %s should not be a valid GUID", value)
+		}
+	}
+}
+
+func TestTaskJournalNewGUID(t *testing.T) {
+	var journal TaskJournal
+	first := journal.New("First task")
+	time.Sleep(2 * time.Millisecond)
+	second := journal.New("Second task")
+	// The GUIDs are sorted by creation, even within the same second
+	if second.GUID <= first.GUID {
+		t.Errorf("The GUID %s should be after %s", second.GUID, first.GUID)
+	}
+	if first.GIndex == second.GIndex {
+		t.Errorf("The GIndex %d should be unique in the journal", first.GIndex)
+	}
+}
+
+func TestTaskSetGlobalID(t *testing.T) {
+	task := CreateTestTask(1, "A task")
+	var read Task
+	if !read.setGlobalID(task.globalID()) || read.GUID != task.GUID {
+		t.Errorf("The GUID is %s (should be %s)", read.GUID, task.GUID)
+	}
+	// The GIndex of a task with no GUID is valid, but it is not an identity
+	read = Task{}
+	if !read.setGlobalID("202401011234567890") || read.GIndex != NoUID || read.GUID != "" {
+		t.Errorf("The global IDs %s/%d should not be set", read.GUID, read.GIndex)
+	}
+	if read.setGlobalID("not-an-id") {
+		t.Error("The value not-an-id should not be a valid global identifier")
+	}
+}
+
+func TestTaskJournalReserve(t *testing.T) {
+	var archive TaskJournal
+	archived := CreateTestTask(1, "An archived task")
+	archived.UIndex = archived.GIndex
+	archive.Add(archived)
+
+	journal := CreateTestJournal()
+	journal.Reserve(archive)
+	// A task imported with the identifiers of the archived task
+	uidmap, err := journal.Import(TaskArray{archived})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, _ := journal.GetTask(uidmap[archived.UIndex])
+	if task.GIndex == archived.GIndex || task.GUID == archived.GUID {
+		t.Errorf("The global IDs %s/%d are used by the archive", task.GUID, task.GIndex)
+	}
+
+	// A task moved from another context keeps its GUID
+	other := CreateTestTask(1, "A task of another context")
+	uidmap, _ = journal.Import(TaskArray{other})
+	task, _ = journal.GetTask(uidmap[other.UIndex])
+	if task.GUID != other.GUID || task.GIndex != other.GIndex {
+		t.Errorf("The global IDs are %s/%d (should be %s/%d)", task.GUID, task.GIndex, other.GUID, other.GIndex)
+	}
+}
+
+func TestTaskJournalGlobalIDs(t *testing.T) {
+	// Two tasks with the same text, created at the same time
+	var journal TaskJournal
+	first := *journal.New("same task")
+	second := *journal.New("same task")
+	if first.GUID == second.GUID || first.GIndex == second.GIndex {
+		t.Errorf("The global IDs should differ: %s/%d and %s/%d", first.GUID, first.GIndex, second.GUID, second.GIndex)
+	}
+}
+
+func TestTaskJournalMigrateGlobalIDs(t *testing.T) {
+	journal := CreateTestJournal()
+	journal.filepath = filepath.Join(t.TempDir(), JournalFilename)
+	for i := range journal.TaskList {
+		journal.TaskList[i].GUID = ""
+	}
+	// Two tasks with the same GIndex and the same note file, and a task with
+	// the GIndex of an archived task
+	journal.TaskList[1].GIndex = journal.TaskList[0].GIndex
+	task := &journal.TaskList[0]
+	task.NotePath = filepath.Join(NotebookDirname, task.noteBasename())
+	journal.TaskList[1].NotePath = task.NotePath
+	notepath := journal.absNotePath(*task)
+	os.MkdirAll(filepath.Dir(notepath), 0755)
+	WriteBytes(notepath, []byte("note"))
+	var archive TaskJournal
+	archive.Add(Task{UIndex: journal.TaskList[2].GIndex, GIndex: journal.TaskList[2].GIndex})
+	journal.Reserve(archive)
+
+	if unmigrated := journal.UnmigratedGlobalIDs(); len(unmigrated) != len(journal.TaskList) {
+		t.Errorf("The tasks to migrate are %v", unmigrated)
+	}
+	nmigrated, oldpaths, err := journal.MigrateGlobalIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oldpaths) != 1 || oldpaths[0] != notepath {
+		t.Errorf("The former notes are %v (should be %v)", oldpaths, []string{notepath})
+	}
+	if nmigrated != len(journal.TaskList) {
+		t.Errorf("Nb migrated tasks is %d (should be %d)", nmigrated, len(journal.TaskList))
+	}
+	gindeces := make(map[TaskID]bool)
+	for _, task := range journal.TaskList {
+		if task.GUID == "" || gindeces[task.GIndex] || task.GIndex == archive.TaskList[0].UIndex {
+			t.Errorf("The global IDs of the task %d are not unique: %s/%d", task.UIndex, task.GUID, task.GIndex)
+		}
+		gindeces[task.GIndex] = true
+	}
+
+	// The note is copied for both tasks, after their GUID
+	for _, task := range journal.TaskList[:2] {
+		if filepath.Base(task.NotePath) != task.GUID+".rst" {
+			t.Errorf("The note path is %s (should be named after %s)", task.NotePath, task.GUID)
+		}
+		if exists, _ := PathExists(journal.absNotePath(task)); !exists {
+			t.Errorf("The note %s should exist", journal.absNotePath(task))
+		}
+	}
+
+	if nmigrated, _, _ = journal.MigrateGlobalIDs(); nmigrated != 0 {
+		t.Errorf("Nb migrated tasks is %d (should be %d)", nmigrated, 0)
+	}
+}
@@ -0,0 +1,317 @@
+package todo
+
+// Implementation of the HTML report of a journal (see HTMLReport) and of the
+// static site of several contexts (see HTMLSite). The pages are
+// self-contained (the style sheet is embedded and there is no script), so that
+// they could be published on any static file share.
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// HTMLIndexFilename is the name of the index page of the static site
+const HTMLIndexFilename = "index.html"
+
+const htmlStyle = `body { font-family: Helvetica, Arial, sans-serif; font-size: 15px; color: #222; max-width: 56em; margin: 2em auto; padding: 0 1em; }
+header { border-bottom: 1px solid #888; margin-bottom: 1em; }
+h1 { margin-bottom: 0.2em; }
+h2 { border-bottom: 1px solid #ccc; margin-top: 1.5em; }
+nav a, .date { color: #777; margin-right: 1em; }
+details > details, details > .task { margin-left: 1.4em; }
+summary { cursor: pointer; }
+.task { padding: 0.15em 0; }
+.uid { display: inline-block; min-width: 2em; font-weight: bold; text-align: right; margin-right: 0.4em; }
+.info { color: #777; font-size: 80%; margin-left: 0.8em; }
+.summary span { margin-right: 1.5em; }
+pre { background: #f4f4f4; padding: 0.6em; overflow-x: auto; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 1em; border-bottom: 1px solid #ddd; text-align: left; }
+footer { color: #777; font-size: 80%; margin-top: 3em; }
+`
+
+// htmlStatusStyle returns the style sheet of the status symbols, whose colors
+// are the colors of the terminal rendering (see taskStatusColors)
+func htmlStatusStyle() string {
+	s := ""
+	for status := StatusStart; status <= StatusEnd; status++ {
+		color := pdfColors[taskStatusColors[status]]
+		s += fmt.Sprintf(".status-%s { color: rgb(%d, %d, %d); }\n", status.Label(),
+			int(255*color.r), int(255*color.g), int(255*color.b))
+	}
+	return s
+}
+
+// htmlPage returns a complete HTML page with the given title and body
+func htmlPage(title string, body string) string {
+	s := "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n"
+	s += "<meta charset=\"utf-8\">\n"
+	s += "<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n"
+	s += fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title))
+	s += "<style>\n" + htmlStyle + htmlStatusStyle() + "</style>\n"
+	s += "</head>\n<body>\n"
+	s += "<header>\n"
+	s += fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title))
+	s += fmt.Sprintf("<p class=\"date\">%s</p>\n", time.Now().Format("Monday, January 2, 2006"))
+	s += "</header>\n"
+	s += body
+	s += "<footer>Generated by todogo</footer>\n"
+	s += "</body>\n</html>\n"
+	return s
+}
+
+// htmlStatus returns the HTML representation of the status symbol
+func htmlStatus(status TaskStatus) string {
+	return fmt.Sprintf("<span class=\"status status-%s\" title=\"%s\">%s</span>",
+		status.Label(), status.Label(), taskStatusPretty[status])
+}
+
+// htmlTask returns the HTML representation of the task on one line (the
+// description is a link to the note of the task, if any)
+func htmlTask(task Task, withNotes bool) string {
+	description := html.EscapeString(task.Description)
+	if task.NotePath != "" && withNotes {
+		description = fmt.Sprintf("<a href=\"#note-%d\">%s</a>", task.UIndex, description)
+	}
+	info := datelabel(task.Timestamp)
+	if task.Due != 0 {
+		info += " - due " + datelabel(task.Due)
+	}
+	if task.OnBoard {
+		info += " - board"
+	}
+	return fmt.Sprintf("%s <span class=\"uid\">%d</span>%s<span class=\"info\">%s</span>",
+		htmlStatus(task.Status), task.UIndex, description, info)
+}
+
+// htmlTree returns the collapsible tree of the tasks (a task with children is
+// a details element whose summary is the task)
+func htmlTree(tasks TaskArray, withNotes bool) string {
+	type node struct {
+		task  Task
+		depth int
+	}
+	nodes := make([]node, 0, len(tasks))
+	walkTree(tasks, TaskFilterAll, func(task Task, depth int) {
+		nodes = append(nodes, node{task: task, depth: depth})
+	})
+
+	s := ""
+	open := 0 // number of open details elements
+	for i, n := range nodes {
+		for ; open > n.depth; open-- {
+			s += "</details>\n"
+		}
+		if i+1 < len(nodes) && nodes[i+1].depth > n.depth {
+			s += fmt.Sprintf("<details open>\n<summary class=\"task\">%s</summary>\n", htmlTask(n.task, withNotes))
+			open++
+		} else {
+			s += fmt.Sprintf("<div class=\"task\">%s</div>\n", htmlTask(n.task, withNotes))
+		}
+	}
+	for ; open > 0; open-- {
+		s += "</details>\n"
+	}
+	return s
+}
+
+// htmlList returns the list of the tasks
+func htmlList(tasks TaskArray, withNotes bool) string {
+	s := ""
+	for _, task := range tasks {
+		s += fmt.Sprintf("<div class=\"task\">%s</div>\n", htmlTask(task, withNotes))
+	}
+	return s
+}
+
+// htmlSummary returns the number of tasks by status of the given tasks
+func htmlSummary(tasks TaskArray) string {
+	s := "<p class=\"summary\">"
+	for status := StatusStart; status <= StatusEnd; status++ {
+		count := len(tasks.indeces(func(task Task) bool { return task.Status == status }))
+		s += fmt.Sprintf("<span>%s %d %s</span>", htmlStatus(status), count, status.Label())
+	}
+	return s + "</p>\n"
+}
+
+// htmlLink matches the URLs of a text (already escaped)
+var htmlLink = regexp.MustCompile(`https?://[^\s<>"]+[^\s<>".,;:!?)]`)
+
+// htmlInline returns the HTML representation of a text of a note, where the
+// inline markups of reStructuredText (literals, strong emphasis) and the URLs
+// are rendered.
+func htmlInline(text string) string {
+	parts := strings.Split(html.EscapeString(text), "``")
+	for i := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + parts[i] + "</code>"
+			continue
+		}
+		if i%2 == 1 {
+			parts[i] = "``" + parts[i]
+		}
+		strong := strings.Split(parts[i], "**")
+		for j := 1; j < len(strong)-1; j += 2 {
+			strong[j] = "<strong>" + strong[j] + "</strong>"
+		}
+		parts[i] = strings.Join(strong, "")
+		parts[i] = htmlLink.ReplaceAllString(parts[i], `<a href="$0">$0</a>`)
+	}
+	return strings.Join(parts, "")
+}
+
+// htmlNote returns the HTML representation of the content of a note (see
+// noteBlocks for the supported reStructuredText structures)
+func htmlNote(content string) string {
+	s := ""
+	inList := false
+	for _, block := range noteBlocks(content) {
+		if block.kind != blockBullet && inList {
+			s += "</ul>\n"
+			inList = false
+		}
+		text := strings.TrimSpace(strings.Join(block.lines, " "))
+		switch block.kind {
+		case blockTitle:
+			s += fmt.Sprintf("<h4>%s</h4>\n", htmlInline(text))
+		case blockBullet:
+			if !inList {
+				s += "<ul>\n"
+				inList = true
+			}
+			s += fmt.Sprintf("<li>%s</li>\n", htmlInline(text))
+		case blockLiteral:
+			s += fmt.Sprintf("<pre>%s</pre>\n", html.EscapeString(strings.Join(block.lines, "\n")))
+		default:
+			if text != "" {
+				s += fmt.Sprintf("<p>%s</p>\n", htmlInline(text))
+			}
+		}
+	}
+	if inList {
+		s += "</ul>\n"
+	}
+	return s
+}
+
+// htmlNotes returns the notes of the tasks that satisfy the filter
+func (journal TaskJournal) htmlNotes(taskFilter TaskFilter) string {
+	s := ""
+	for _, task := range journal.TaskList {
+		if task.NotePath == "" || !taskFilter(task) {
+			continue
+		}
+		content, err := journal.GetNoteContent(task.UIndex)
+		if err != nil {
+			content = err.Error()
+		}
+		s += fmt.Sprintf("<section id=\"note-%d\">\n<h3>%s %d - %s</h3>\n", task.UIndex,
+			htmlStatus(task.Status), task.UIndex, html.EscapeString(task.Description))
+		s += htmlNote(content)
+		s += "</section>\n"
+	}
+	if s == "" {
+		s = "<p><em>No notes</em></p>\n"
+	}
+	return s
+}
+
+// htmlSections returns the HTML representation of the given sections of the
+// report, restricted to the tasks that satisfy the filter.
+func (journal TaskJournal) htmlSections(sections []ReportSection, taskFilter TaskFilter) string {
+	withNotes := false
+	for _, section := range sections {
+		withNotes = withNotes || section == SectionNotes
+	}
+	s := ""
+	if len(sections) > 1 {
+		s += "<nav>"
+		for _, section := range sections {
+			s += fmt.Sprintf("<a href=\"#%s\">%s</a>", strings.ToLower(section.Title()), section.Title())
+		}
+		s += "</nav>\n"
+	}
+	tasks := make(TaskArray, 0, len(journal.TaskList))
+	for _, task := range journal.TaskList {
+		if taskFilter(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	for _, section := range sections {
+		s += fmt.Sprintf("<section id=\"%s\">\n<h2>%s</h2>\n", strings.ToLower(section.Title()), section.Title())
+		switch section {
+		case SectionTree:
+			s += htmlSummary(tasks)
+			s += htmlTree(tasks, withNotes)
+		case SectionBoard:
+			board := make(TaskArray, 0)
+			for _, task := range tasks {
+				if task.OnBoard {
+					board = append(board, task)
+				}
+			}
+			s += htmlSummary(board)
+			s += htmlList(board, withNotes)
+		case SectionNotes:
+			s += journal.htmlNotes(taskFilter)
+		default:
+			s += htmlSummary(tasks)
+			s += htmlList(tasks, withNotes)
+		}
+		s += "</section>\n"
+	}
+	return s
+}
+
+// HTMLReport returns a self-contained HTML page made of the given sections
+// (ReportSections for a complete report), restricted to the tasks that
+// satisfy the filter. The tree of the tasks is collapsible, and the notes are
+// rendered from their reStructuredText content.
+func (journal TaskJournal) HTMLReport(title string, sections []ReportSection, taskFilter TaskFilter) string {
+	return htmlPage(title, journal.htmlSections(sections, taskFilter))
+}
+
+// HTMLFilename returns the name of the page of the context in the static site
+func HTMLFilename(context Context) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, context.Name)
+	return fmt.Sprintf("context-%s.html", name)
+}
+
+// HTMLSite returns the pages of a static site made of an index page (named
+// HTMLIndexFilename) that links the complete report of each context (see
+// HTMLFilename). The result maps the file names to the contents of the pages.
+func (journals ContextJournalArray) HTMLSite(title string) map[string]string {
+	pages := make(map[string]string)
+	index := "<table>\n<tr><th>Context</th>"
+	for status := StatusStart; status <= StatusEnd; status++ {
+		index += fmt.Sprintf("<th>%s %s</th>", htmlStatus(status), status.Label())
+	}
+	index += "<th>Board</th></tr>\n"
+	for _, contextJournal := range journals {
+		filename := HTMLFilename(contextJournal.Context)
+		tasks := contextJournal.Journal.TaskList
+		index += fmt.Sprintf("<tr><td><a href=\"%s\">%s</a></td>", filename, html.EscapeString(contextJournal.Context.Name))
+		for status := StatusStart; status <= StatusEnd; status++ {
+			count := len(tasks.indeces(func(task Task) bool { return task.Status == status }))
+			index += fmt.Sprintf("<td>%d</td>", count)
+		}
+		index += fmt.Sprintf("<td>%d</td></tr>\n", len(tasks.indeces(TaskFilterOnBoard)))
+
+		pageTitle := fmt.Sprintf("%s - %s", title, contextJournal.Context.Name)
+		body := fmt.Sprintf("<nav><a href=\"%s\">%s</a></nav>\n", HTMLIndexFilename, html.EscapeString(title))
+		body += contextJournal.Journal.htmlSections(ReportSections, TaskFilterAll)
+		pages[filename] = htmlPage(pageTitle, body)
+	}
+	index += "</table>\n"
+	pages[HTMLIndexFilename] = htmlPage(title, index)
+	return pages
+}
@@ -0,0 +1,76 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLReport(t *testing.T) {
+	tasks := createTreeTaskArray()
+	journal := TaskJournal{TaskList: tasks}
+	ptask, _ := journal.GetTask(12)
+	ptask.Description = "B.2.2 <b>&"
+	ptask.OnBoard = true
+
+	page := journal.HTMLReport("Tree", ReportSections, TaskFilterAll)
+	printlog(page)
+
+	if strings.Count(page, "<details") != strings.Count(page, "</details>") {
+		t.Error("The details elements are not balanced")
+	}
+	// One details element for each task with children
+	ndetails := 0
+	tree := make(treeMap, 0)
+	tree.initialize(tasks)
+	for parentID, children := range tree {
+		if parentID != NoUID && len(children) > 0 {
+			ndetails++
+		}
+	}
+	if strings.Count(page, "<details") != ndetails {
+		t.Errorf("Nb details is %d (should be %d)", strings.Count(page, "<details"), ndetails)
+	}
+	if !strings.Contains(page, "B.2.2 &lt;b&gt;&amp;") {
+		t.Error("The description should be escaped")
+	}
+	for _, section := range ReportSections {
+		if !strings.Contains(page, "<h2>"+section.Title()+"</h2>") {
+			t.Errorf("The section %s is missing", section.Title())
+		}
+	}
+
+	filtered := journal.HTMLReport("Board", []ReportSection{SectionList}, TaskFilterOnBoard)
+	if strings.Count(filtered, "class=\"task\"") != 1 {
+		t.Errorf("Nb tasks is %d (should be %d)", strings.Count(filtered, "class=\"task\""), 1)
+	}
+}
+
+func TestHTMLInline(t *testing.T) {
+	text := "Use ``a < b`` with **care**, see https://example.com/x?a=1&b=2."
+	result := htmlInline(text)
+	expected := `Use <code>a &lt; b</code> with <strong>care</strong>, see <a href="https://example.com/x?a=1&amp;b=2">https://example.com/x?a=1&amp;b=2</a>.`
+	if result != expected {
+		t.Errorf("The result is %s (should be %s)", result, expected)
+	}
+}
+
+func TestHTMLSite(t *testing.T) {
+	journals := ContextJournalArray{
+		{Context: Context{Name: "work"}, Journal: CreateTestJournal()},
+		{Context: Context{Name: "home/garden"}, Journal: TaskJournal{TaskList: createTreeTaskArray()}},
+	}
+	pages := journals.HTMLSite("Team")
+	if len(pages) != 3 {
+		t.Fatalf("Nb pages is %d (should be %d)", len(pages), 3)
+	}
+	index := pages[HTMLIndexFilename]
+	for _, contextJournal := range journals {
+		filename := HTMLFilename(contextJournal.Context)
+		if strings.Contains(filename, "/") {
+			t.Errorf("The file name %s is not valid", filename)
+		}
+		if _, exists := pages[filename]; !exists || !strings.Contains(index, filename) {
+			t.Errorf("The page %s is missing", filename)
+		}
+	}
+}
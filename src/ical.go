@@ -0,0 +1,287 @@
+package todo
+
+// Implementation of the iCalendar format (RFC 5545), with one VTODO component
+// per task:
+//
+//   BEGIN:VTODO
+//   UID:<GUID>
+//   SUMMARY:<Description>
+//   STATUS:NEEDS-ACTION | IN-PROCESS | COMPLETED
+//   RELATED-TO;RELTYPE=PARENT:<GUID of the parent>
+//   DUE;VALUE=DATE:<Due>
+//   DESCRIPTION:<content of the note>
+//   END:VTODO
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	icalProdID     = "-//galuma//todogo//EN"
+	icalLayoutUTC  = "20060102T150405Z"
+	icalLayoutTime = "20060102T150405"
+	icalLayoutDate = "20060102"
+	icalLineLength = 75
+	icalBoard      = "BOARD"
+)
+
+var icalStatus = map[TaskStatus]string{
+	StatusTodo:  "NEEDS-ACTION",
+	StatusDoing: "IN-PROCESS",
+	StatusDone:  "COMPLETED",
+}
+
+// icalEscape escapes the special characters of an iCalendar text value
+func icalEscape(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+// icalUnescape is the reverse function of icalEscape
+func icalUnescape(text string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(text)
+}
+
+// icalFold returns the content line folded to lines of at most 75 octets
+// (the continuation lines start with a space), ended with CRLF.
+func icalFold(line string) string {
+	s := ""
+	limit := icalLineLength
+	for len(line) > limit {
+		// The line is not cut inside a multi-byte character
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		s += line[:cut] + "\r\n "
+		line = line[cut:]
+		limit = icalLineLength - 1
+	}
+	return s + line + "\r\n"
+}
+
+// icalDateTime returns the iCalendar UTC date-time of the timestamp
+func icalDateTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(icalLayoutUTC)
+}
+
+// ICalendar returns the iCalendar representation of the tasks of this journal
+// that satisfy the given filter (a VCALENDAR with one VTODO per task).
+func (journal TaskJournal) ICalendar(taskFilter TaskFilter) string {
+	s := icalFold("BEGIN:VCALENDAR")
+	s += icalFold("VERSION:2.0")
+	s += icalFold("PRODID:" + icalProdID)
+	now := icalDateTime(timestamp())
+	for _, task := range journal.TaskList {
+		if !taskFilter(task) {
+			continue
+		}
+		s += icalFold("BEGIN:VTODO")
+		s += icalFold("UID:" + task.globalID())
+		s += icalFold("DTSTAMP:" + now)
+		s += icalFold("CREATED:" + icalDateTime(task.Timestamp))
+		s += icalFold("SUMMARY:" + icalEscape(task.Description))
+		s += icalFold("STATUS:" + icalStatus[task.Status])
+		if completion := task.CompletionDate(); completion != 0 {
+			s += icalFold("COMPLETED:" + icalDateTime(completion))
+		}
+		if task.ParentID != NoUID {
+			if parent, err := journal.GetTask(task.ParentID); err == nil {
+				s += icalFold("RELATED-TO;RELTYPE=PARENT:" + parent.globalID())
+			}
+		}
+		if task.Due != 0 {
+			s += icalFold("DUE;VALUE=DATE:" + time.Unix(task.Due, 0).Format(icalLayoutDate))
+		}
+		if len(task.Priority) == 1 && task.Priority[0] >= 'A' && task.Priority[0] <= 'I' {
+			s += icalFold(fmt.Sprintf("PRIORITY:%d", task.Priority[0]-'A'+1))
+		}
+		if task.OnBoard {
+			s += icalFold("CATEGORIES:" + icalBoard)
+		}
+		if body, err := journal.GetNoteContent(task.UIndex); err == nil && body != "" {
+			s += icalFold("DESCRIPTION:" + icalEscape(body))
+		}
+		s += icalFold("END:VTODO")
+	}
+	s += icalFold("END:VCALENDAR")
+	return s
+}
+
+// icalProperty is a content line of an iCalendar component
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICalProperty splits a content line into name, parameters and value
+func parseICalProperty(line string) (icalProperty, error) {
+	property := icalProperty{params: make(map[string]string)}
+	// The value starts after the first colon that is not in a quoted
+	// parameter value
+	quoted := false
+	idx := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return property, fmt.Errorf("ERR: the line %s is not an iCalendar content line", line)
+	}
+	property.value = line[idx+1:]
+	parts := strings.Split(line[:idx], ";")
+	property.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			property.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return property, nil
+}
+
+// parseICalDate parses an iCalendar date or date-time value
+func parseICalDate(value string) (int64, error) {
+	layouts := []string{icalLayoutUTC, icalLayoutTime, icalLayoutDate}
+	for _, layout := range layouts {
+		location := time.Local
+		if layout == icalLayoutUTC {
+			location = time.UTC
+		}
+		date, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return date.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("ERR: the date %s is not a valid iCalendar date", value)
+}
+
+// ParseICalendar parses the VTODO components of the iCalendar text and
+// returns the list of tasks and the contents of their notes (the DESCRIPTION
+// of the components). The UIndex of the tasks are the order numbers of the
+// components and the parent relations (RELATED-TO with RELTYPE PARENT, the
+// default) are resolved with the UID of the components. The UID of a component
+// is the GUID of the task only if it is a ULID, as written by todo (the UID of
+// a foreign calendar is not an identity of the task). The completion date
+// (COMPLETED) is recorded only for the completed components. The other
+// components are ignored.
+func ParseICalendar(text string) (TaskArray, map[TaskID]string, error) {
+	// Unfolding of the content lines
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	tasks := make(TaskArray, 0)
+	notes := make(map[TaskID]string)
+	uids := make(map[string]TaskID)    // UID of the components => UIndex
+	parents := make(map[TaskID]string) // UIndex => UID of the parent component
+
+	var task *Task
+	var completion int64 // COMPLETED of the current VTODO
+	depth := 0           // depth of the nested components in the VTODO (e.g. VALARM)
+	for _, line := range lines {
+		property, err := parseICalProperty(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := strings.ToUpper(property.value)
+		if property.name == "BEGIN" && value == "VTODO" {
+			task = &Task{
+				UIndex:    TaskID(len(tasks) + 1),
+				Timestamp: timestamp(),
+				Status:    StatusTodo,
+			}
+			completion = 0
+			continue
+		}
+		if task == nil {
+			continue
+		}
+		if property.name == "BEGIN" {
+			depth++
+			continue
+		}
+		if property.name == "END" {
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if task.Status == StatusDone && completion != 0 {
+				task.History = append(task.History, StatusChange{Status: StatusDone, Timestamp: completion})
+			}
+			tasks = append(tasks, *task)
+			task = nil
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+
+		switch property.name {
+		case "UID":
+			uids[property.value] = task.UIndex
+			if _, ok := decodeGUID(property.value); ok {
+				task.setGlobalID(property.value)
+			}
+		case "SUMMARY":
+			task.Description = icalUnescape(property.value)
+		case "DESCRIPTION":
+			notes[task.UIndex] = icalUnescape(property.value)
+		case "STATUS":
+			for status, label := range icalStatus {
+				if label == value {
+					task.Status = status
+				}
+			}
+		case "CREATED":
+			task.Timestamp, err = parseICalDate(property.value)
+		case "DUE":
+			task.Due, err = parseICalDate(property.value)
+		case "COMPLETED":
+			completion, err = parseICalDate(property.value)
+		case "PRIORITY":
+			priority, _ := strconv.Atoi(property.value)
+			if priority >= 1 && priority <= 9 {
+				task.Priority = string(rune('A' + priority - 1))
+			}
+		case "CATEGORIES":
+			for _, category := range strings.Split(value, ",") {
+				task.OnBoard = task.OnBoard || category == icalBoard
+			}
+		case "RELATED-TO":
+			reltype, exists := property.params["RELTYPE"]
+			if !exists || strings.ToUpper(reltype) == "PARENT" {
+				parents[task.UIndex] = property.value
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := 0; i < len(tasks); i++ {
+		if parentUID, exists := parents[tasks[i].UIndex]; exists {
+			tasks[i].ParentID = uids[parentUID]
+		}
+	}
+	return tasks, notes, nil
+}
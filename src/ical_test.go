@@ -0,0 +1,94 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalendar(t *testing.T) {
+	journal := CreateTestJournal()
+	ptask, _ := journal.GetTask(2)
+	ptask.ParentID = 1
+	ptask.Description = "Create unit tests, for todogo; with a long description to fold on several lines"
+	ptask.Due, _ = ParseDate("2026-11-02")
+	ptask.NextStatus()
+	ptask, _ = journal.GetTask(3)
+	ptask.NextStatus()
+	ptask.NextStatus()
+
+	text := journal.ICalendar(TaskFilterAll)
+	printlog(text)
+	for _, line := range strings.Split(text, "\r\n") {
+		if len(line) > icalLineLength {
+			t.Errorf("The line %s is longer than %d octets", line, icalLineLength)
+		}
+	}
+
+	tasks, _, err := ParseICalendar(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(journal.TaskList) {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), len(journal.TaskList))
+	}
+	for i, task := range tasks {
+		init := journal.TaskList[i]
+		if task.Description != init.Description || task.Status != init.Status || task.GUID != init.GUID {
+			t.Errorf("Description is %s (should be %s)", task.Description, init.Description)
+		}
+		if task.Due != init.Due || task.Timestamp != init.Timestamp {
+			t.Errorf("Due date is %d (should be %d)", task.Due, init.Due)
+		}
+	}
+	if tasks[1].ParentID != tasks[0].UIndex {
+		t.Errorf("ParentID is %d (should be %d)", tasks[1].ParentID, tasks[0].UIndex)
+	}
+	if tasks[2].CompletionDate() == 0 {
+		t.Error("The completion date should be defined")
+	}
+}
+
+func TestParseICalendar(t *testing.T) {
+	text := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:event\r\nSUMMARY:Not a task\r\nEND:VEVENT\r\n" +
+		"BEGIN:VTODO\r\nUID:abc-123\r\nSUMMARY:Parent\r\nSTATUS:IN-PROCESS\r\n" +
+		"BEGIN:VALARM\r\nDESCRIPTION:Alarm\r\nEND:VALARM\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:def-456\r\nSUMMARY:Child\r\nRELATED-TO:abc-123\r\n" +
+		"DESCRIPTION:A note\\non two\r\n  lines\r\nDUE:20261102T100000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	tasks, notes, err := ParseICalendar(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 2)
+	}
+	if tasks[0].Status != StatusDoing || tasks[1].ParentID != tasks[0].UIndex {
+		t.Errorf("ParentID is %d (should be %d)", tasks[1].ParentID, tasks[0].UIndex)
+	}
+	if notes[tasks[1].UIndex] != "A note\non two lines" || notes[tasks[0].UIndex] != "" {
+		t.Errorf("The note is %q (should be %q)", notes[tasks[1].UIndex], "A note\non two lines")
+	}
+}
+
+func TestParseICalendarIdentity(t *testing.T) {
+	guid := newGUID(time.Now())
+	text := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:" + guid + "\r\nSUMMARY:Exported by todo\r\nSTATUS:COMPLETED\r\n" +
+		"COMPLETED:20260102T100000Z\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:1234\r\nSUMMARY:Foreign\r\nCOMPLETED:20260102T100000Z\r\n" +
+		"STATUS:NEEDS-ACTION\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	tasks, _, err := ParseICalendar(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks[0].GUID != guid || tasks[0].CompletionDate() == 0 {
+		t.Errorf("The GUID is %s (should be %s)", tasks[0].GUID, guid)
+	}
+	// The foreign UID is not an identity, and the task is not completed
+	if tasks[1].GUID != "" || tasks[1].GIndex != NoUID {
+		t.Errorf("The task %s should have no global identifier (%s, %d)", tasks[1].Description, tasks[1].GUID, tasks[1].GIndex)
+	}
+	if tasks[1].Status != StatusTodo || len(tasks[1].History) != 0 {
+		t.Errorf("The task %s should not be completed (%s)", tasks[1].Description, tasks[1].Status.Label())
+	}
+}
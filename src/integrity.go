@@ -0,0 +1,114 @@
+package todo
+
+// Implementation of the checks of the integrity of a journal, i.e. of the
+// parent relations between the tasks. A task whose parent does not exist is an
+// orphan. The orphans are left by the former versions of todo, that deleted or
+// archived the tasks without updating their children, and they are silently
+// adopted by the next task created with the free UID of their parent. The
+// former versions could also create cycles of parent relations (a task being
+// an ancestor of itself).
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Orphan is a task whose parent does not exist in its journal
+type Orphan struct {
+	UIndex   TaskID // The UID of the orphaned task
+	ParentID TaskID // The UID of the parent that does not exist
+}
+
+// String returns a string representation of this orphan
+func (orphan Orphan) String() string {
+	return fmt.Sprintf("The task %d refers to the parent %d that does not exist", orphan.UIndex, orphan.ParentID)
+}
+
+// Orphans returns the tasks of this journal whose parent does not exist
+func (journal TaskJournal) Orphans() []Orphan {
+	orphans := make([]Orphan, 0)
+	for _, task := range journal.TaskList {
+		if task.ParentID == NoUID {
+			continue
+		}
+		if journal.TaskList.indexFromUID(task.ParentID) == noIndex {
+			orphans = append(orphans, Orphan{task.UIndex, task.ParentID})
+		}
+	}
+	return orphans
+}
+
+// RepairOrphans makes root tasks of the tasks of this journal whose parent
+// does not exist (the former parent, and then its own parent, are unknown).
+// Returns the repaired orphans.
+func (journal *TaskJournal) RepairOrphans() []Orphan {
+	orphans := journal.Orphans()
+	for _, orphan := range orphans {
+		task, _ := journal.GetTask(orphan.UIndex)
+		task.ParentID = NoUID
+	}
+	return orphans
+}
+
+// Cycle is a list of tasks whose parent relations form a cycle: each task is
+// the parent of the previous one, and the first task is the parent of the last
+// one.
+type Cycle TaskIDArray
+
+// String returns a string representation of this cycle
+func (cycle Cycle) String() string {
+	uids := make([]string, len(cycle))
+	for i, uindex := range cycle {
+		uids[i] = fmt.Sprint(uindex)
+	}
+	return fmt.Sprintf("The tasks %s form a cycle of parent relations", strings.Join(uids, ", "))
+}
+
+// Cycles returns the cycles of parent relations of this journal
+func (journal TaskJournal) Cycles() []Cycle {
+	const (
+		unvisited = iota
+		walking   // The task is on the current walk to the root
+		visited
+	)
+	cycles := make([]Cycle, 0)
+	state := make(map[TaskID]int)
+	for _, task := range journal.TaskList {
+		walk := make(TaskIDArray, 0)
+		uindex := task.UIndex
+		for state[uindex] == unvisited {
+			state[uindex] = walking
+			walk = append(walk, uindex)
+			ptask, err := journal.GetTask(uindex)
+			if err != nil || ptask.ParentID == NoUID {
+				uindex = NoUID
+				break
+			}
+			uindex = ptask.ParentID
+		}
+		// The walk has come back to one of its tasks
+		if uindex != NoUID && state[uindex] == walking {
+			for i, uid := range walk {
+				if uid == uindex {
+					cycles = append(cycles, Cycle(walk[i:]))
+					break
+				}
+			}
+		}
+		for _, uid := range walk {
+			state[uid] = visited
+		}
+	}
+	return cycles
+}
+
+// RepairCycles breaks the cycles of parent relations of this journal, by making
+// a root task of the first task of each cycle. Returns the repaired cycles.
+func (journal *TaskJournal) RepairCycles() []Cycle {
+	cycles := journal.Cycles()
+	for _, cycle := range cycles {
+		task, _ := journal.GetTask(cycle[0])
+		task.ParentID = NoUID
+	}
+	return cycles
+}
@@ -0,0 +1,234 @@
+package todo
+
+import (
+	"testing"
+)
+
+func TestTaskJournalDeleteReparent(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+
+	// B.2 (8) is the parent of 11 and 12, and the child of B (31)
+	_, err := journal.Delete(8)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, uindex := range []TaskID{11, 12} {
+		task, _ := journal.GetTask(uindex)
+		if task.ParentID != 31 {
+			t.Errorf("The parent of %d is %d (should be %d)", uindex, task.ParentID, 31)
+		}
+	}
+	// B (31) is a root task
+	_, err = journal.Delete(31)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, uindex := range []TaskID{7, 11, 12} {
+		task, _ := journal.GetTask(uindex)
+		if task.ParentID != NoUID {
+			t.Errorf("The parent of %d is %d (should be %d)", uindex, task.ParentID, NoUID)
+		}
+	}
+	if len(journal.Orphans()) != 0 {
+		t.Errorf("The journal should have no orphan: %v", journal.Orphans())
+	}
+}
+
+func TestTaskJournalMove(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	var target TaskJournal
+
+	// Move B.2 (8) and B.2.2 (12), but not B.2.1 (11) and B.2.2.1 (13)
+	newUID := func(task Task) TaskID { return 100 + task.UIndex }
+	uids, err := journal.Move(&target, TaskIDArray{8, 12}, newUID)
+	if err != nil {
+		t.Error(err)
+	}
+	if uids[8] != 108 || uids[12] != 112 {
+		t.Errorf("The new UIDs are %v", uids)
+	}
+
+	expected := map[TaskID]TaskID{108: NoUID, 112: 108}
+	for uindex, parentID := range expected {
+		task, err := target.GetTask(uindex)
+		if err != nil {
+			t.Error(err)
+		} else if task.ParentID != parentID {
+			t.Errorf("The parent of %d is %d (should be %d)", uindex, task.ParentID, parentID)
+		}
+	}
+	expected = map[TaskID]TaskID{11: 31, 13: 31}
+	for uindex, parentID := range expected {
+		task, _ := journal.GetTask(uindex)
+		if task.ParentID != parentID {
+			t.Errorf("The parent of %d is %d (should be %d)", uindex, task.ParentID, parentID)
+		}
+	}
+	if len(journal.Orphans())+len(target.Orphans()) != 0 {
+		t.Error("The journals should have no orphan")
+	}
+
+	_, err = journal.Move(&target, TaskIDArray{8}, newUID)
+	if err == nil {
+		t.Error("The task 8 does not exist anymore and should raise an error")
+	}
+}
+
+func TestTaskJournalMoveRoundTrip(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	for i := 0; i < len(journal.TaskList); i++ {
+		journal.TaskList[i].initGlobalIndex()
+	}
+	var archive TaskJournal
+	checkParent := func(journal TaskJournal, uindex TaskID, parentID TaskID) {
+		t.Helper()
+		task, err := journal.GetTask(uindex)
+		if err != nil {
+			t.Error(err)
+		} else if task.ParentID != parentID {
+			t.Errorf("The parent of %d is %d (should be %d)", uindex, task.ParentID, parentID)
+		}
+	}
+
+	// Archive B.2 (8) without its children B.2.1 (11) and B.2.2 (12)
+	archiveUID := func(task Task) TaskID { return 100 + task.UIndex }
+	_, err := journal.Move(&archive, TaskIDArray{8}, archiveUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParent(archive, 108, NoUID)
+	checkParent(journal, 11, 31)
+	checkParent(journal, 12, 31)
+
+	// Archive B.2.2 (12), that is a child of B.2 (8) again in the archive
+	_, err = journal.Move(&archive, TaskIDArray{12}, archiveUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParent(archive, 112, 108)
+	checkParent(journal, 13, 31)
+
+	// Restore B.2 (8) and B.2.2 (12): the relations with B (31), B.2.1 (11)
+	// and B.2.2.1 (13) are restored
+	restoreUID := func(task Task) TaskID { return task.UIndex + 100 }
+	_, err = archive.Move(&journal, TaskIDArray{108, 112}, restoreUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[TaskID]TaskID{208: 31, 212: 208, 11: 208, 13: 212}
+	for uindex, parentID := range expected {
+		checkParent(journal, uindex, parentID)
+	}
+	for _, task := range journal.TaskList {
+		if task.ParentGUID != "" {
+			t.Errorf("The relation of the task %d should have been restored", task.UIndex)
+		}
+	}
+	if len(journal.Orphans())+len(archive.Orphans()) != 0 {
+		t.Error("The journals should have no orphan")
+	}
+}
+
+func TestTaskJournalRepairOrphans(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	// Simulate the deletion of B.2 (8) without updating its children
+	index := journal.TaskList.indexFromUID(8)
+	journal.TaskList.remove(index)
+
+	orphans := journal.Orphans()
+	if len(orphans) != 2 {
+		t.Errorf("Nb orphans is %d (should be %d)", len(orphans), 2)
+	}
+	for _, orphan := range orphans {
+		if orphan.ParentID != 8 {
+			t.Errorf("The parent of the orphan %d is %d (should be %d)", orphan.UIndex, orphan.ParentID, 8)
+		}
+	}
+
+	repaired := journal.RepairOrphans()
+	if len(repaired) != 2 {
+		t.Errorf("Nb repaired orphans is %d (should be %d)", len(repaired), 2)
+	}
+	if len(journal.Orphans()) != 0 {
+		t.Errorf("The journal should have no orphan: %v", journal.Orphans())
+	}
+	task, _ := journal.GetTask(12)
+	if task.ParentID != NoUID {
+		t.Errorf("The parent of %d is %d (should be %d)", 12, task.ParentID, NoUID)
+	}
+}
+
+func TestTaskJournalSetParent(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+
+	parentOf := func(uindex TaskID) TaskID {
+		task, _ := journal.GetTask(uindex)
+		return task.ParentID
+	}
+
+	// A task can not be a child of itself or of one of its descendants
+	if err := journal.SetParent(8, 8, true); err == nil {
+		t.Error("The task 8 can not be a child of itself")
+	}
+	if err := journal.SetParent(8, 13, true); err == nil {
+		t.Error("The task 8 can not be a child of its descendant 13")
+	}
+	if err := journal.SetParent(8, 99, true); err == nil {
+		t.Error("The task 99 does not exist and should raise an error")
+	}
+	if parentOf(8) != 31 {
+		t.Errorf("The parent of %d is %d (should be %d)", 8, parentOf(8), 31)
+	}
+
+	// Move B.2.2 (12) with its child B.2.2.1 (13) under A (30)
+	if err := journal.SetParent(12, 30, true); err != nil {
+		t.Error(err)
+	}
+	if parentOf(12) != 30 || parentOf(13) != 12 {
+		t.Errorf("The parents of 12 and 13 are %d and %d (should be 30 and 12)", parentOf(12), parentOf(13))
+	}
+
+	// Move B.2 (8) under its child B.2.1 (11) without its children
+	if err := journal.SetParent(8, 11, false); err != nil {
+		t.Error(err)
+	}
+	if parentOf(8) != 11 || parentOf(11) != 31 {
+		t.Errorf("The parents of 8 and 11 are %d and %d (should be 11 and 31)", parentOf(8), parentOf(11))
+	}
+
+	// Unparent the task B.2.1 (11)
+	if err := journal.SetParent(11, NoUID, true); err != nil {
+		t.Error(err)
+	}
+	if parentOf(11) != NoUID || parentOf(8) != 11 {
+		t.Errorf("The parents of 11 and 8 are %d and %d (should be %d and 11)", parentOf(11), parentOf(8), NoUID)
+	}
+	if len(journal.Cycles()) != 0 {
+		t.Errorf("The journal should have no cycle: %v", journal.Cycles())
+	}
+}
+
+func TestTaskJournalRepairCycles(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	// Simulate the cycles B.2 -> B.2.2.1 -> B.2.2 -> B.2 and D -> D
+	ptask, _ := journal.GetTask(8)
+	ptask.ParentID = 13
+	ptask, _ = journal.GetTask(14)
+	ptask.ParentID = 14
+
+	cycles := journal.Cycles()
+	if len(cycles) != 2 {
+		t.Errorf("Nb cycles is %d (should be %d)", len(cycles), 2)
+	}
+	for _, cycle := range cycles {
+		printlog(cycle.String())
+		if cycle[0] == 14 && len(cycle) != 1 || cycle[0] != 14 && len(cycle) != 3 {
+			t.Errorf("The cycle %v is not valid", cycle)
+		}
+	}
+
+	journal.RepairCycles()
+	if len(journal.Cycles()) != 0 {
+		t.Errorf("The journal should have no cycle: %v", journal.Cycles())
+	}
+}
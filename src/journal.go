@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -16,24 +17,31 @@ import (
 // could be the current collection of tasks (called journal) or the archive
 // collection of tasks (called archive).
 type TaskJournal struct {
-	TaskList TaskArray
-	filepath string
+	TaskList       TaskArray
+	ShortIDCounter uint64 `json:",omitempty"` // Counter of the short IDs of the tasks
+	filepath       string
+	reserved       TaskArray // Tasks whose global identifiers are reserved (see Reserve)
 }
 
 // =========================================================================
 // Implementation of the edition functions
 
-// New creates a new task in the database
+// New creates a new task in the database. The GUID of the task is created at
+// the current time (in milliseconds), so that the GUIDs of the tasks created in
+// the same second are sorted by creation.
 func (journal *TaskJournal) New(text string) *Task {
 	uindex := journal.TaskList.getFreeUID()
+	now := time.Now()
 	var task = Task{
 		UIndex:      uindex,
+		GUID:        newGUID(now),
+		ShortID:     journal.nextShortID(),
 		Description: text,
-		Timestamp:   timestamp(),
+		Timestamp:   now.Unix(),
 		Status:      StatusTodo,
 		OnBoard:     false,
 	}
-	task.initGlobalIndex()
+	journal.initGlobalIndex(&task)
 	journal.TaskList.append(task)
 	ptask, _ := journal.GetTask(task.UIndex)
 	return ptask
@@ -44,8 +52,9 @@ func (journal *TaskJournal) Add(task Task) error {
 	return journal.TaskList.append(task)
 }
 
-// Delete removes the task with the specified id. Returns a copy of the deleted
-// task on success
+// Delete removes the task with the specified id. The children of the deleted
+// task are given its parent, so that they never refer to a free UID (that could
+// be reused by a new task). Returns a copy of the deleted task on success
 func (journal *TaskJournal) Delete(uindex TaskID) (Task, error) {
 	var task Task
 	index := journal.TaskList.indexFromUID(uindex)
@@ -54,7 +63,19 @@ func (journal *TaskJournal) Delete(uindex TaskID) (Task, error) {
 	}
 	task = journal.TaskList[index]
 	err := journal.TaskList.remove(index)
-	return task, err
+	if err != nil {
+		return task, err
+	}
+	parentID := task.ParentID
+	if parentID == uindex {
+		parentID = NoUID
+	}
+	for i := 0; i < len(journal.TaskList); i++ {
+		if journal.TaskList[i].ParentID == uindex {
+			journal.TaskList[i].ParentID = parentID
+		}
+	}
+	return task, nil
 }
 
 // GetTask returns a pointer to the task whose usage ID is uindex
@@ -80,9 +101,24 @@ func (journal TaskJournal) GetTaskInfo(uindex TaskID) (string, error) {
 	s := ""
 	s += fmt.Sprintf("Task               : %s\n", task.Description)
 	s += fmt.Sprintf("Usage Index  (UID) : %d\n", task.UIndex)
+	if task.ShortID != "" {
+		s += fmt.Sprintf("Short ID           : %s\n", task.ShortID)
+	}
 	s += fmt.Sprintf("Global Index (GID) : %d\n", task.GIndex)
+	if task.GUID != "" {
+		s += fmt.Sprintf("Global ID   (GUID) : %s\n", task.GUID)
+	}
 	s += fmt.Sprintf("Creation Date      : %s\n", datelabel(task.Timestamp))
 	s += fmt.Sprintf("Status             : %s\n", task.Status.Label())
+	if task.CompletionDate() != 0 {
+		s += fmt.Sprintf("Completion Date    : %s\n", datelabel(task.CompletionDate()))
+	}
+	if task.Priority != "" {
+		s += fmt.Sprintf("Priority           : %s\n", task.Priority)
+	}
+	if task.Due != 0 {
+		s += fmt.Sprintf("Due Date           : %s\n", datelabel(task.Due))
+	}
 	s += fmt.Sprintf("Is on board        : %v\n", task.OnBoard)
 	s += fmt.Sprintf("Note filepath      : %s\n", notepath)
 	s += fmt.Sprintf("Parent UID         : %d", task.ParentID)
@@ -121,6 +157,351 @@ func (journal *TaskJournal) RemoveFromBoard(uindex TaskID) error {
 	return nil
 }
 
+// SetParent makes the task uindex a child of the task parentID (or a root task
+// if parentID is NoUID). If subtree is true, then the task is moved with all
+// its descendants, else its children are given its former parent. Returns an
+// error if a task does not exist or if the new parent relation would create a
+// cycle (a task can not be a child of itself or of one of its descendants).
+func (journal *TaskJournal) SetParent(uindex TaskID, parentID TaskID, subtree bool) error {
+	task, err := journal.GetTask(uindex)
+	if err != nil {
+		return err
+	}
+	if parentID != NoUID {
+		if _, err := journal.GetTask(parentID); err != nil {
+			return err
+		}
+	}
+	if parentID == uindex {
+		return fmt.Errorf("ERR: The task %d can not be a child of itself", uindex)
+	}
+	if subtree && journal.TaskList.ancestor(parentID, uindex) {
+		return fmt.Errorf("ERR: The task %d can not be a child of its descendant %d", uindex, parentID)
+	}
+
+	if !subtree {
+		formerParentID := task.ParentID
+		if formerParentID == uindex {
+			formerParentID = NoUID
+		}
+		for i := 0; i < len(journal.TaskList); i++ {
+			if journal.TaskList[i].ParentID == uindex {
+				journal.TaskList[i].ParentID = formerParentID
+			}
+		}
+	}
+	task.ParentID = parentID
+	task.ParentGUID = ""
+	return nil
+}
+
+// CascadeStatus applies the status modifier (e.g. (*Task).NextStatus) to the
+// descendants of the task uindex, once the modifier has been applied to this
+// task. A descendant is changed only if the modifier moves it toward the new
+// status of the task, so that it never goes past this status (e.g. a doing
+// child of a task changed from todo to doing is left unchanged). Returns the
+// UIDs of the descendants whose status has changed.
+func (journal *TaskJournal) CascadeStatus(uindex TaskID, modifier func(*Task) error) (TaskIDArray, error) {
+	uids, err := journal.WithDescendants(TaskIDArray{uindex})
+	if err != nil {
+		return nil, err
+	}
+	ptask, _ := journal.GetTask(uindex)
+	distance := func(status TaskStatus) int {
+		if status > ptask.Status {
+			return int(status - ptask.Status)
+		}
+		return int(ptask.Status - status)
+	}
+	changed := make(TaskIDArray, 0, len(uids))
+	for _, uid := range uids {
+		if uid == uindex {
+			continue
+		}
+		task, _ := journal.GetTask(uid)
+		modified := *task
+		if modifier(&modified) == nil && distance(modified.Status) < distance(task.Status) {
+			*task = modified
+			changed = append(changed, uid)
+		}
+	}
+	return changed, nil
+}
+
+// =========================================================================
+// Implementation of the transfer functions (tasks exchanged between journals)
+
+// WithDescendants returns the specified UIDs together with the UIDs of all the
+// descendants of these tasks. Each UID is listed once, and a task is listed
+// before its descendants.
+func (journal TaskJournal) WithDescendants(uindeces TaskIDArray) (TaskIDArray, error) {
+	tree := make(treeMap, 0)
+	tree.initialize(journal.TaskList)
+
+	// The tasks that are descendants of another specified task are listed
+	// with the descendants of this task, so that a task is always listed
+	// before its descendants.
+	covered := make(map[TaskID]bool)
+	for _, uindex := range uindeces {
+		if journal.TaskList.indexFromUID(uindex) == noIndex {
+			return nil, fmt.Errorf("ERR: The task %d does not exist", uindex)
+		}
+		for _, uid := range tree.descendants(uindex) {
+			covered[uid] = true
+		}
+	}
+
+	selected := make(map[TaskID]bool)
+	uids := make(TaskIDArray, 0, len(uindeces))
+	add := func(uindex TaskID) {
+		for _, uid := range append(TaskIDArray{uindex}, tree.descendants(uindex)...) {
+			if !selected[uid] {
+				selected[uid] = true
+				uids = append(uids, uid)
+			}
+		}
+	}
+	for _, uindex := range uindeces {
+		if !covered[uindex] {
+			add(uindex)
+		}
+	}
+	for _, uindex := range uindeces {
+		// The tasks that are all covered by a cycle of parent relations
+		add(uindex)
+	}
+	return uids, nil
+}
+
+// Subtree returns a copy of the specified tasks together with all their
+// descendants. The NotePath of the copies is set to the absolute path of the
+// note files, so that the tasks can be imported in another journal.
+func (journal TaskJournal) Subtree(uindeces TaskIDArray) (TaskArray, error) {
+	uids, err := journal.WithDescendants(uindeces)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(TaskArray, 0, len(uids))
+	for _, uid := range uids {
+		task := journal.TaskList[journal.TaskList.indexFromUID(uid)]
+		if task.NotePath != "" {
+			task.NotePath = journal.absNotePath(task)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// SubtreeFilter returns a filter that selects the task uindex and all its
+// descendants.
+func (journal TaskJournal) SubtreeFilter(uindex TaskID) (TaskFilter, error) {
+	tasks, err := journal.Subtree(TaskIDArray{uindex})
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[TaskID]bool)
+	for _, task := range tasks {
+		selected[task.UIndex] = true
+	}
+	return func(task Task) bool { return selected[task.UIndex] }, nil
+}
+
+// Import adds the given tasks to this journal. The tasks are given a free UID
+// of this journal, and the parent relations between the imported tasks are
+// remapped to the new UIDs (a parent that is not part of the imported tasks is
+// reset to NoUID). The GUID is preserved unless it is already used in this
+// journal (or reserved, see Reserve), and the GIndex is derived again from the
+// GUID and made unique in this journal. If a task has a note, then NotePath
+// should be the absolute path to the source note file, which is copied in the
+// notebook of this journal. Returns the map from the imported UIDs to the new
+// UIDs.
+func (journal *TaskJournal) Import(tasks TaskArray) (map[TaskID]TaskID, error) {
+	return journal.ImportWithNotes(tasks, nil)
+}
+
+// ImportWithNotes adds the given tasks to this journal (see Import), and
+// creates the notes whose contents are given in the notes map (the key is the
+// UID of the imported task).
+func (journal *TaskJournal) ImportWithNotes(tasks TaskArray, notes map[TaskID]string) (map[TaskID]TaskID, error) {
+	uidmap := make(map[TaskID]TaskID)
+
+	for _, task := range tasks {
+		olduid := task.UIndex
+		task.UIndex = journal.TaskList.getFreeUID()
+		journal.initGlobalIndex(&task)
+		task.ShortID = journal.nextShortID()
+		srcpath := task.NotePath
+		task.NotePath = ""
+		err := journal.TaskList.append(task)
+		if err != nil {
+			return uidmap, err
+		}
+		uidmap[olduid] = task.UIndex
+		if srcpath != "" {
+			err = journal.importNoteFile(task.UIndex, srcpath)
+			if err != nil {
+				return uidmap, err
+			}
+		}
+		if body := notes[olduid]; body != "" {
+			err = journal.SetNoteContent(task.UIndex, body)
+			if err != nil {
+				return uidmap, err
+			}
+		}
+	}
+
+	for _, newuid := range uidmap {
+		task, _ := journal.GetTask(newuid)
+		if parentuid, exists := uidmap[task.ParentID]; exists {
+			task.ParentID = parentuid
+		} else {
+			task.ParentID = NoUID
+		}
+	}
+	return uidmap, nil
+}
+
+// importNoteFile copies the note file srcpath as the note file of the task
+// uindex (in the notebook of this journal).
+func (journal *TaskJournal) importNoteFile(uindex TaskID, srcpath string) error {
+	task, err := journal.GetTask(uindex)
+	if err != nil {
+		return err
+	}
+	task.NotePath = filepath.Join(NotebookDirname, task.noteBasename())
+	return CopyFile(srcpath, journal.absNotePath(*task))
+}
+
+// Move moves the specified tasks from this journal to the target journal,
+// where each task is given the UID returned by the function newUID (the notes
+// are not moved). The parent relations between the moved tasks are remapped to
+// the new UIDs, and a moved task whose parent is not moved becomes a root task.
+// The tasks that stay in this journal are given their closest ancestor that is
+// not moved (see Delete). The relations between the moved tasks and the tasks
+// that stay are recorded with the GUID of the parent (ParentGUID), and they are
+// restored when the tasks are moved back (e.g. an archived task restored in the
+// journal, see relinkParents). Returns the map from the UIDs in this journal to
+// the UIDs in the target journal.
+func (journal *TaskJournal) Move(target *TaskJournal, uindeces TaskIDArray, newUID func(Task) TaskID) (map[TaskID]TaskID, error) {
+	tasks := make(TaskArray, 0, len(uindeces))
+	moved := make(map[TaskID]bool)
+	for _, uindex := range uindeces {
+		task, err := journal.GetTask(uindex)
+		if err != nil {
+			return nil, err
+		}
+		if !moved[uindex] {
+			tasks = append(tasks, *task)
+			moved[uindex] = true
+		}
+	}
+
+	// A relation already recorded (the parent is in another journal) is kept
+	parentGUID := func(task Task) string {
+		parent, err := journal.GetTask(task.ParentID)
+		if task.ParentGUID != "" || err != nil {
+			return task.ParentGUID
+		}
+		return parent.GUID
+	}
+	for i := 0; i < len(tasks); i++ {
+		if !moved[tasks[i].ParentID] {
+			tasks[i].ParentGUID = parentGUID(tasks[i])
+		}
+	}
+	for i := 0; i < len(journal.TaskList); i++ {
+		task := &journal.TaskList[i]
+		if moved[task.ParentID] && !moved[task.UIndex] {
+			task.ParentGUID = parentGUID(*task)
+		}
+	}
+
+	uidmap := make(map[TaskID]TaskID)
+	for _, task := range tasks {
+		olduid := task.UIndex
+		task.UIndex = newUID(task)
+		err := target.Add(task)
+		if err != nil {
+			return uidmap, err
+		}
+		_, err = journal.Delete(olduid)
+		if err != nil {
+			return uidmap, err
+		}
+		uidmap[olduid] = task.UIndex
+	}
+
+	for _, task := range tasks {
+		ptask, _ := target.GetTask(uidmap[task.UIndex])
+		if parentuid, moved := uidmap[task.ParentID]; moved {
+			ptask.ParentID = parentuid
+		} else {
+			ptask.ParentID = NoUID
+		}
+	}
+	target.relinkParents()
+	return uidmap, nil
+}
+
+// relinkParents gives back their parent to the tasks whose parent was in
+// another journal (see Move), if the parent is now in this journal.
+func (journal *TaskJournal) relinkParents() {
+	for i := 0; i < len(journal.TaskList); i++ {
+		task := &journal.TaskList[i]
+		if task.ParentGUID == "" {
+			continue
+		}
+		index := journal.TaskList.index(func(parent Task) bool { return parent.GUID == task.ParentGUID })
+		if index == noIndex {
+			continue
+		}
+		parentID := journal.TaskList[index].UIndex
+		if parentID != task.UIndex && !journal.TaskList.ancestor(parentID, task.UIndex) {
+			task.ParentID = parentID
+			task.ParentGUID = ""
+		}
+	}
+}
+
+// Transfer moves the specified tasks, with all their descendants and their note
+// files, from this journal to the target journal. If copy is true, then the
+// tasks are copied (and given new global indeces) instead of moved. Returns the
+// map from the UIDs in this journal to the UIDs in the target journal, and the
+// paths of the note files of the moved tasks. The note files are copied in the
+// notebook of the target journal, and the source files should be removed by
+// the caller once both journals are saved.
+func (journal *TaskJournal) Transfer(target *TaskJournal, uindeces TaskIDArray, copy bool) (map[TaskID]TaskID, []string, error) {
+	tasks, err := journal.Subtree(uindeces)
+	if err != nil {
+		return nil, nil, err
+	}
+	if copy {
+		for i := 0; i < len(tasks); i++ {
+			tasks[i].GIndex = NoUID
+			tasks[i].GUID = ""
+		}
+	}
+
+	uidmap, err := target.Import(tasks)
+	if err != nil || copy {
+		return uidmap, nil, err
+	}
+
+	notepaths := make([]string, 0)
+	for _, task := range tasks {
+		if task.NotePath != "" {
+			notepaths = append(notepaths, task.NotePath)
+		}
+		_, err = journal.Delete(task.UIndex)
+		if err != nil {
+			return uidmap, notepaths, err
+		}
+	}
+	return uidmap, notepaths, nil
+}
+
 // =========================================================================
 // Implementation of the serialization functions
 
@@ -196,20 +577,31 @@ const notasks = "No tasks. Go have a drink"
 // returns true).
 func (journal TaskJournal) ListWithFilter(taskFilter TaskFilter) string {
 	s := fmt.Sprintln()
+	listing, nlisted := journal.listTasks(taskFilter, Task.String)
+	s += listing
+	if nlisted == 0 {
+		s += fmt.Sprintf("%s\n\n", notasks)
+	} else {
+		s += fmt.Sprintf("\n%s\n", legendString())
+	}
+	return s
+}
+
+// listTasks returns the lines of the tasks that satisfy the given filter, each
+// task being represented with the taskString function, and the number of
+// listed tasks.
+func (journal TaskJournal) listTasks(taskFilter TaskFilter, taskString func(Task) string) (string, int) {
+	s := ""
 	nlisted := 0
+	taskString = journal.TaskList.withProgress(taskString)
 	for i := 0; i < len(journal.TaskList); i++ {
 		task := journal.TaskList[i]
 		if taskFilter(task) {
-			s += fmt.Sprintf("%s\n", task.String())
+			s += fmt.Sprintf("%s\n", taskString(task))
 			nlisted++
 		}
 	}
-	if nlisted == 0 {
-		s += fmt.Sprintf("%s\n\n", notasks)
-	} else {
-		s += fmt.Sprintf("\nLegend: %s  %s  %s\n", StatusTodo.legend(), StatusDoing.legend(), StatusDone.legend())
-	}
-	return s
+	return s, nlisted
 }
 
 // List returns a string representation of the list of all tasks (no filter)
@@ -219,18 +611,31 @@ func (journal TaskJournal) List() string {
 
 // Tree returns a string representation of the tree structure of tasks (parent relations)
 func (journal TaskJournal) Tree() string {
-	if len(journal.TaskList) == 0 {
-		return fmt.Sprintf("\n%s\n\n", notasks)
+	s, _ := journal.TreeWithOptions(TreeOptions{})
+	return s
+}
+
+// TreeWithOptions returns a string representation of the tree structure of
+// the tasks, restricted by the options (see TreeOptions). Returns an error if
+// the root task of the options does not exist.
+func (journal TaskJournal) TreeWithOptions(options TreeOptions) (string, error) {
+	if options.Root != NoUID {
+		if _, err := journal.GetTask(options.Root); err != nil {
+			return "", err
+		}
+	}
+	tree := treeString(journal.TaskList, Task.String, options)
+	if tree == "" {
+		return fmt.Sprintf("\n%s\n\n", notasks), nil
 	}
 	s := ""
-	tree := TreeString(journal.TaskList)
 	if tree[0] != '\n' {
 		// We add a line return for a pretty look
 		s += fmt.Sprintln()
 	}
 	s += tree
-	s += fmt.Sprintf("\nLegend: %s  %s  %s\n", StatusTodo.legend(), StatusDoing.legend(), StatusDone.legend())
-	return s
+	s += fmt.Sprintf("\n%s\n", legendString())
+	return s, nil
 }
 
 func (journal TaskJournal) String() string {
@@ -240,6 +645,16 @@ func (journal TaskJournal) String() string {
 // =========================================================================
 // Implementation of the functions to edit task features
 
+// absNotePath returns the absolute path of the note file of the given task. A
+// relative NotePath is considered as relative to the journal root directory.
+func (journal TaskJournal) absNotePath(task Task) string {
+	if filepath.IsAbs(task.NotePath) {
+		return task.NotePath
+	}
+	rootdir := filepath.Dir(journal.File())
+	return filepath.Join(rootdir, task.NotePath)
+}
+
 func (journal *TaskJournal) getNoteFile(uindex TaskID, create bool) (string, error) {
 	task, err := journal.GetTask(uindex)
 	if err != nil {
@@ -252,17 +667,10 @@ func (journal *TaskJournal) getNoteFile(uindex TaskID, create bool) (string, err
 		}
 		// initializes the NotePath with the default value (path relative to the
 		// context root directory)
-		basename := fmt.Sprintf("%d.rst", task.GIndex)
-		task.NotePath = filepath.Join(NotebookDirname, basename)
+		task.NotePath = filepath.Join(NotebookDirname, task.noteBasename())
 	}
 
-	var notepath string
-	if filepath.IsAbs(task.NotePath) {
-		notepath = task.NotePath
-	} else {
-		rootdir := filepath.Dir(journal.File())
-		notepath = filepath.Join(rootdir, task.NotePath)
-	}
+	notepath := journal.absNotePath(*task)
 
 	exists, err := PathExists(notepath)
 	if exists && err != nil {
@@ -310,6 +718,49 @@ func (journal *TaskJournal) GetOrCreateNoteFile(uindex TaskID) (string, error) {
 	return journal.getNoteFile(uindex, true)
 }
 
+// noteBody returns the content of a note without its title (the title created
+// by GetOrCreateNoteFile, i.e. a line underlined with "=")
+func noteBody(content string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) >= 2 && lines[1] != "" && strings.Trim(lines[1], "=") == "" {
+		lines = lines[2:]
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// GetNoteContent returns the content of the note of the task, without the
+// title of the note. Returns a blank string ("") if the task has no note.
+func (journal TaskJournal) GetNoteContent(uindex TaskID) (string, error) {
+	task, err := journal.GetTask(uindex)
+	if err != nil || task.NotePath == "" {
+		return "", err
+	}
+	content, err := LoadString(journal.absNotePath(*task))
+	if err != nil {
+		return "", err
+	}
+	return noteBody(content), nil
+}
+
+// SetNoteContent writes the given content in the note of the task, after the
+// title of the note. The note file is created if it does not exist.
+func (journal *TaskJournal) SetNoteContent(uindex TaskID, body string) error {
+	notepath, err := journal.GetOrCreateNoteFile(uindex)
+	if err != nil {
+		return err
+	}
+	content, err := LoadString(notepath)
+	if err != nil {
+		return err
+	}
+	lines := strings.SplitN(content, "\n", 3)
+	title := ""
+	if len(lines) >= 2 && lines[1] != "" && strings.Trim(lines[1], "=") == "" {
+		title = lines[0] + "\n" + lines[1] + "\n"
+	}
+	return WriteBytes(notepath, []byte(fmt.Sprintf("%s\n%s\n", title, strings.Trim(body, "\n"))))
+}
+
 func (journal *TaskJournal) DeleteNoteFile(uindex TaskID) error {
 	task, err := journal.GetTask(uindex)
 	if err != nil {
@@ -320,15 +771,7 @@ func (journal *TaskJournal) DeleteNoteFile(uindex TaskID) error {
 		return errors.New("the task has no associated note")
 	}
 
-	var notepath string
-	if filepath.IsAbs(task.NotePath) {
-		notepath = task.NotePath
-	} else {
-		rootdir := filepath.Dir(journal.File())
-		notepath = filepath.Join(rootdir, task.NotePath)
-	}
-
-	err = os.Remove(notepath)
+	err = os.Remove(journal.absNotePath(*task))
 	if err != nil {
 		return err
 	}
@@ -339,20 +782,13 @@ func (journal *TaskJournal) DeleteNoteFile(uindex TaskID) error {
 // ListNotes returns the list of all task notes as a single concatenated string
 func (journal TaskJournal) ListNotes() string {
 	listNotes := ""
-	var notepath string
 	for _, task := range journal.TaskList {
 
 		if task.NotePath == "" {
 			continue
 		}
 
-		if filepath.IsAbs(task.NotePath) {
-			notepath = task.NotePath
-		} else {
-			rootdir := filepath.Dir(journal.File())
-			notepath = filepath.Join(rootdir, task.NotePath)
-		}
-		content, err := LoadString(notepath)
+		content, err := LoadString(journal.absNotePath(task))
 		if err != nil {
 			listNotes += err.Error() + "\n"
 		} else {
@@ -362,16 +798,56 @@ func (journal TaskJournal) ListNotes() string {
 	return listNotes
 }
 
-// Report returns a complete report including (1) the task tree, (2) the task
-// board and (3) the notes contents
+// ReportSection identifies a section of the report of a journal
+type ReportSection int
+
+// Enumeration of the possible ReportSection
+const (
+	SectionList ReportSection = iota
+	SectionTree
+	SectionBoard
+	SectionNotes
+)
+
+var reportSectionTitles = map[ReportSection]string{
+	SectionList:  "Tasks",
+	SectionTree:  "Tree",
+	SectionBoard: "Board",
+	SectionNotes: "Notes",
+}
+
+// ReportSections is the list of the sections of a complete report: (1) the
+// task tree, (2) the task board and (3) the notes contents
+var ReportSections = []ReportSection{SectionTree, SectionBoard, SectionNotes}
+
+// Title returns the title of this section
+func (section ReportSection) Title() string {
+	return reportSectionTitles[section]
+}
+
+// sectionString returns the string representation of the section of the report
+func (journal TaskJournal) sectionString(section ReportSection) string {
+	switch section {
+	case SectionTree:
+		return journal.Tree()
+	case SectionBoard:
+		return journal.ListWithFilter(TaskFilterOnBoard)
+	case SectionNotes:
+		return "\n" + journal.ListNotes()
+	}
+	return journal.List()
+}
+
+// Report returns a complete report made of the sections ReportSections
 func (journal TaskJournal) Report() string {
-	report := journal.Tree()
-	report += "\n------------------------------------------------------\n"
-	report += "Board:\n"
-	report += journal.ListWithFilter(TaskFilterOnBoard)
-	report += "\n------------------------------------------------------\n"
-	report += "Notes:\n\n"
-	report += journal.ListNotes()
+	report := ""
+	for i, section := range ReportSections {
+		if i > 0 {
+			report += "\n------------------------------------------------------\n"
+			report += section.Title() + ":\n"
+		}
+		report += journal.sectionString(section)
+	}
 	return report
 }
 
@@ -0,0 +1,223 @@
+package todo
+
+// Implementation of the kanban view of the board: one column per status, side
+// by side, with the tasks as cards whose descriptions are wrapped to the width
+// of the columns:
+//
+//   TODO (2)               | DOING (3/2) !          | DONE (1)
+//   ---------------------- | ====================== | ----------------------
+//   o 4 Write the user     | > 2 Create unit tests  | x 1 Write the
+//       guide              | > 3 Add a function to  |     documentation
+//   o 5 Review the code    |     print a journal    |
+//
+// The header of a column gives the number of tasks and the WIP (work in
+// progress) limit of the status, and is highlighted when the limit is
+// exceeded. The WIP limits are configuration parameters (0 for no limit).
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	kanbanSeparator      = " | "
+	kanbanMinColumnWidth = 12
+	// KanbanDefaultWidth is the width of the kanban view when the width of
+	// the terminal is unknown
+	KanbanDefaultWidth = 80
+)
+
+var kanbanStatuses = []TaskStatus{StatusTodo, StatusDoing, StatusDone}
+
+// WIPLimits returns the WIP limit of each status (0 for no limit)
+func (parameters Parameters) WIPLimits() map[TaskStatus]int {
+	return map[TaskStatus]int{
+		StatusTodo:  parameters.WIPLimitTodo,
+		StatusDoing: parameters.WIPLimitDoing,
+		StatusDone:  parameters.WIPLimitDone,
+	}
+}
+
+// padRight pads the text with spaces up to the given width (in characters)
+func padRight(text string, width int) string {
+	if size := utf8.RuneCountInString(text); size < width {
+		return text + strings.Repeat(" ", width-size)
+	}
+	return text
+}
+
+// wrapWords splits the text into lines of at most width characters (a word
+// that is larger than the width is cut).
+func wrapWords(text string, width int) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// kanbanCell is a line of a column, with the function that renders it (to
+// add colors once the line is padded to the width of the column)
+type kanbanCell struct {
+	text   string
+	render func(string) string
+}
+
+func plainCell(text string) kanbanCell {
+	return kanbanCell{text: text, render: func(s string) string { return s }}
+}
+
+// kanbanCard returns the lines of the card of the task: the status symbol and
+// the label of the task followed by its description, wrapped to the width.
+func kanbanCard(task Task, label string, width int) []kanbanCell {
+	if statusRenderingFunction == nil {
+		initRenderingTools()
+	}
+	prefix := fmt.Sprintf("%s %s ", renderingMap[task.Status], label)
+	indent := utf8.RuneCountInString(prefix)
+	if indent > width/2 {
+		indent = 2
+	}
+	lines := wrapWords(task.Description, width-indent)
+	cells := make([]kanbanCell, 0, len(lines))
+	for i, line := range lines {
+		if i == 0 && indent == utf8.RuneCountInString(prefix) {
+			text := prefix + line
+			cells = append(cells, kanbanCell{text: text, render: func(s string) string {
+				symbol := renderingMap[task.Status]
+				return task.Status.String() + strings.TrimPrefix(s, symbol)
+			}})
+			continue
+		}
+		if i == 0 {
+			// The label is too large to be followed by the description
+			cells = append(cells, plainCell(prefix))
+		}
+		cells = append(cells, plainCell(strings.Repeat(" ", indent)+line))
+	}
+	return cells
+}
+
+// kanbanTask is a task of the kanban view, with the label that identifies it
+type kanbanTask struct {
+	task  Task
+	label string
+}
+
+// kanbanString returns the kanban view of the tasks in a view of the given
+// width.
+func kanbanString(tasks []kanbanTask, width int) string {
+	cfg, _ := GetConfig() // unused to test the err, we can not arrive here in case of config error
+	limits := cfg.Parameters.WIPLimits()
+	highlight := renderingFunctionMap[false]
+	if cfg.Parameters.WithColor {
+		highlight = func(s string) string { return ColorString(s, ColorRed) }
+	}
+
+	separatorsWidth := utf8.RuneCountInString(kanbanSeparator) * (len(kanbanStatuses) - 1)
+	columnWidth := (width - separatorsWidth) / len(kanbanStatuses)
+	if columnWidth < kanbanMinColumnWidth {
+		columnWidth = kanbanMinColumnWidth
+	}
+
+	columns := make([][]kanbanCell, len(kanbanStatuses))
+	nrows := 0
+	for i, status := range kanbanStatuses {
+		cards := make([]kanbanCell, 0)
+		count := 0
+		for _, item := range tasks {
+			if item.task.Status == status {
+				cards = append(cards, kanbanCard(item.task, item.label, columnWidth)...)
+				count++
+			}
+		}
+		header := fmt.Sprintf("%s (%d)", strings.ToUpper(status.Label()), count)
+		rule := strings.Repeat("-", columnWidth)
+		render := renderingFunctionMap[false]
+		if limit := limits[status]; limit > 0 {
+			header = fmt.Sprintf("%s (%d/%d)", strings.ToUpper(status.Label()), count, limit)
+			if count > limit {
+				header += " !"
+				rule = strings.Repeat("=", columnWidth)
+				render = highlight
+			}
+		}
+		columns[i] = append([]kanbanCell{{header, render}, {rule, render}}, cards...)
+		if len(columns[i]) > nrows {
+			nrows = len(columns[i])
+		}
+	}
+
+	s := ""
+	for row := 0; row < nrows; row++ {
+		line := ""
+		for i, column := range columns {
+			if i > 0 {
+				line += kanbanSeparator
+			}
+			cell := plainCell("")
+			if row < len(column) {
+				cell = column[row]
+			}
+			line += cell.render(padRight(cell.text, columnWidth))
+		}
+		s += strings.TrimRight(line, " ") + "\n"
+	}
+	return s
+}
+
+// Kanban returns the kanban view of the tasks of this journal that satisfy
+// the given filter (usually the tasks on board), in a view of the given width
+// (in characters).
+func (journal TaskJournal) Kanban(taskFilter TaskFilter, width int) string {
+	tasks := make([]kanbanTask, 0)
+	for _, task := range journal.TaskList {
+		if taskFilter(task) {
+			tasks = append(tasks, kanbanTask{task: task, label: fmt.Sprint(task.UIndex)})
+		}
+	}
+	if len(tasks) == 0 {
+		return fmt.Sprintf("\n%s\n", notasks)
+	}
+	return fmt.Sprintf("\n%s\n%s\n", kanbanString(tasks, width), legendString())
+}
+
+// Kanban returns the kanban view of the tasks of all the journals that
+// satisfy the given filter. The tasks are identified by their address
+// context:uid, and the WIP limits apply to the tasks of all the journals.
+func (journals ContextJournalArray) Kanban(taskFilter TaskFilter, width int) string {
+	tasks := make([]kanbanTask, 0)
+	for _, contextJournal := range journals {
+		for _, task := range contextJournal.Journal.TaskList {
+			if taskFilter(task) {
+				tasks = append(tasks, kanbanTask{task: task, label: contextJournal.label(task)})
+			}
+		}
+	}
+	if len(tasks) == 0 {
+		return fmt.Sprintf("\n%s\n", notasks)
+	}
+	return fmt.Sprintf("\n%s\n%s\n", kanbanString(tasks, width), legendString())
+}
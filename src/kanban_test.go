@@ -0,0 +1,70 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrapWords(t *testing.T) {
+	lines := wrapWords("Write the documentation of todogo", 10)
+	expected := []string{"Write the", "documentat", "ion of", "todogo"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("The lines are %q (should be %q)", lines, expected)
+	}
+	if lines := wrapWords("", 10); len(lines) != 1 || lines[0] != "" {
+		t.Errorf("The lines are %q (should be one empty line)", lines)
+	}
+}
+
+func TestKanban(t *testing.T) {
+	config, err := GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parameters := config.Parameters
+	defer func() { config.Parameters = parameters }()
+	config.Parameters.WithColor = false
+	config.Parameters.WIPLimitDoing = 1
+
+	journal := CreateTestJournal()
+	for i := range journal.TaskList {
+		journal.TaskList[i].OnBoard = true
+	}
+	journal.TaskList[1].Status = StatusDoing
+	journal.TaskList[2].Status = StatusDoing
+	journal.TaskList[3].Status = StatusDone
+
+	width := 60
+	kanban := journal.Kanban(TaskFilterOnBoard, width)
+	printlog(kanban)
+
+	lines := strings.Split(kanban, "\n")
+	if !strings.HasPrefix(lines[1], "TODO (1)") {
+		t.Errorf("The header is %q (should start with TODO (1))", lines[1])
+	}
+	if !strings.Contains(lines[1], "DOING (2/1) !") {
+		t.Errorf("The exceeded WIP limit should be highlighted in %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "=====") {
+		t.Errorf("The rule of the exceeded column should be highlighted in %q", lines[2])
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > width {
+			t.Errorf("The line %q is larger than %d characters", line, width)
+		}
+	}
+	for _, task := range journal.TaskList {
+		card := fmt.Sprintf(" %d %s", task.UIndex, strings.Fields(task.Description)[0])
+		if !strings.Contains(kanban, card) {
+			t.Errorf("The task %d is missing", task.UIndex)
+		}
+	}
+
+	config.Parameters.WIPLimitDoing = 2
+	kanban = journal.Kanban(TaskFilterOnBoard, width)
+	if strings.Contains(kanban, "!") {
+		t.Error("The WIP limit is not exceeded")
+	}
+}
@@ -0,0 +1,123 @@
+package todo
+
+// Implementation of the Markdown format (GitHub-style checklists)
+//
+// The tasks are written as a nested checklist following the parent relations:
+//
+//   - [ ] Project task
+//     - [x] Child task (done)
+//     - [ ] Child task _(doing)_
+//       > Content of the note of the child task
+//
+// The doing status, that has no equivalent in the Markdown checklists, is
+// indicated by the _(doing)_ suffix. The notes are optionally inlined as block
+// quotes under the task item.
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	markdownIndent = "  "
+	markdownDoing  = "_(doing)_"
+)
+
+// markdownItem matches a checklist item: indentation, check mark and text
+var markdownItem = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\] (.*)$`)
+
+// markdownQuote matches a quote line: indentation and text
+var markdownQuote = regexp.MustCompile(`^(\s*)>\s?(.*)$`)
+
+// Markdown returns the Markdown representation of the tasks of this journal
+// that satisfy the given filter, as a nested checklist. If title is not blank,
+// it is used as the header of the document. If notes is true, then the notes
+// are inlined as block quotes under the tasks.
+func (journal TaskJournal) Markdown(title string, taskFilter TaskFilter, notes bool) string {
+	s := ""
+	if title != "" {
+		s += fmt.Sprintf("# %s\n\n", title)
+	}
+	walkTree(journal.TaskList, taskFilter, func(task Task, depth int) {
+		indent := strings.Repeat(markdownIndent, depth)
+		check := " "
+		if task.Status == StatusDone {
+			check = "x"
+		}
+		s += fmt.Sprintf("%s- [%s] %s", indent, check, task.Description)
+		if task.Status == StatusDoing {
+			s += " " + markdownDoing
+		}
+		s += "\n"
+		if !notes {
+			return
+		}
+		body, err := journal.GetNoteContent(task.UIndex)
+		if err != nil || body == "" {
+			return
+		}
+		for _, line := range strings.Split(body, "\n") {
+			s += strings.TrimRight(fmt.Sprintf("%s%s> %s", indent, markdownIndent, line), " ") + "\n"
+		}
+	})
+	return s
+}
+
+// ParseMarkdown parses the checklists of the Markdown text and returns the
+// list of tasks and the contents of their notes (the block quotes under the
+// task items). The parent relations are given by the nesting of the
+// checklists. The UIndex of the tasks are the order numbers of the items, to
+// be remapped with TaskJournal.ImportWithNotes. The lines that are not
+// checklist items or block quotes of an item are ignored.
+func ParseMarkdown(text string) (TaskArray, map[TaskID]string, error) {
+	tasks := make(TaskArray, 0)
+	notes := make(map[TaskID]string)
+
+	type level struct {
+		indent int
+		uid    TaskID
+	}
+	stack := make([]level, 0)
+	var last TaskID = NoUID
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.ReplaceAll(scanner.Text(), "\t", "    ")
+		if match := markdownItem.FindStringSubmatch(line); match != nil {
+			indent := len(match[1])
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			task := Task{
+				UIndex:      TaskID(len(tasks) + 1),
+				Timestamp:   timestamp(),
+				Description: strings.TrimSpace(match[3]),
+				Status:      StatusTodo,
+			}
+			if len(stack) > 0 {
+				task.ParentID = stack[len(stack)-1].uid
+			}
+			if match[2] != " " {
+				task.SetStatus(StatusDone)
+			} else if strings.HasSuffix(task.Description, markdownDoing) {
+				task.SetStatus(StatusDoing)
+			}
+			task.Description = strings.TrimSpace(strings.TrimSuffix(task.Description, markdownDoing))
+			tasks = append(tasks, task)
+			stack = append(stack, level{indent: indent, uid: task.UIndex})
+			last = task.UIndex
+			continue
+		}
+		if match := markdownQuote.FindStringSubmatch(line); match != nil && last != NoUID {
+			notes[last] += match[2] + "\n"
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			// Any other text ends the notes of the last task
+			last = NoUID
+		}
+	}
+	return tasks, notes, scanner.Err()
+}
@@ -0,0 +1,80 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	tasks := createTreeTaskArray()
+	journal := TaskJournal{TaskList: tasks}
+	ptask, _ := journal.GetTask(13)
+	ptask.Status = StatusDone
+	ptask, _ = journal.GetTask(12)
+	ptask.Status = StatusDoing
+
+	text := journal.Markdown("Tree", TaskFilterAll, false)
+	printlog(text)
+
+	// The depth is given by the indentation, and the status by the check box
+	lines := []string{
+		"# Tree\n",
+		"\n- [ ] A\n",
+		"\n  - [ ] B.1\n",
+		"\n    - [ ] B.2.2 _(doing)_\n",
+		"\n      - [x] B.2.2.1\n",
+		"\n- [ ] D\n",
+	}
+	for _, line := range lines {
+		if !strings.Contains(text, line) {
+			t.Errorf("The line %q is missing", line)
+		}
+	}
+
+	parsed, notes, err := ParseMarkdown(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Nb notes is %d (should be %d)", len(notes), 0)
+	}
+	byText := checkTreeRoundTrip(t, parsed)
+	expected := map[string]TaskStatus{"B.2.2.1": StatusDone, "B.2.2": StatusDoing, "B.2": StatusTodo}
+	for description, status := range expected {
+		if byText(description).Status != status {
+			t.Errorf("The status of %s is %s (should be %s)", description, byText(description).Status.Label(), status.Label())
+		}
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	text := `# Plan
+
+Some text before the list
+
+- [ ] First
+  > A note
+  > on two lines
+  * [x] First child
+- [X] Second
+	- [ ] Second child
+
+- a plain item is not a task
+`
+	tasks, notes, err := ParseMarkdown(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 4)
+	}
+	if tasks[1].ParentID != tasks[0].UIndex || tasks[3].ParentID != tasks[2].UIndex {
+		t.Errorf("The parent of %s is %d (should be %d)", tasks[1].Description, tasks[1].ParentID, tasks[0].UIndex)
+	}
+	if notes[tasks[0].UIndex] != "A note\non two lines\n" {
+		t.Errorf("The note is %q (should be %q)", notes[tasks[0].UIndex], "A note\non two lines\n")
+	}
+	if tasks[2].Status != StatusDone {
+		t.Errorf("The status is %s (should be %s)", tasks[2].Status.Label(), "done")
+	}
+}
@@ -0,0 +1,316 @@
+package todo
+
+// Implementation of the Emacs Org-mode format
+//
+// The tasks are written as an Org outline following the parent relations:
+//
+//   #+TODO: TODO DOING | DONE
+//
+//   * TODO [#A] Project task                                        :board:
+//   DEADLINE: <2024-01-20 Sat>
+//   :PROPERTIES:
+//   :GID:      01HK2G3S00Q5RZ1WB7T2VAXJ8M
+//   :CREATED:  [2024-01-01 Mon 10:00]
+//   :END:
+//   Content of the note of the project task
+//   ** DONE Child task
+//   CLOSED: [2024-01-10 Wed 10:00]
+//
+// The lines of the notes that could be read as Org syntax (headlines and
+// keyword lines) are escaped with a comma, as Org does in its blocks.
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	orgLayoutDate     = "2006-01-02 Mon"
+	orgLayoutDateTime = "2006-01-02 Mon 15:04"
+	orgTagBoard       = "board"
+)
+
+var orgKeywords = map[TaskStatus]string{
+	StatusTodo:  "TODO",
+	StatusDoing: "DOING",
+	StatusDone:  "DONE",
+}
+
+// orgHeadline matches a headline: stars, keyword and priority (optional),
+// title and tags (optional)
+var orgHeadline = regexp.MustCompile(`^(\*+)\s+(?:([A-Z]+)\s+)?(?:\[#([A-Z])\]\s+)?(.*?)(?:\s+(:[\w@#%:]+:))?\s*$`)
+
+// orgPlanning matches the CLOSED, DEADLINE and SCHEDULED items of a planning line
+var orgPlanning = regexp.MustCompile(`(CLOSED|DEADLINE|SCHEDULED):\s*[\[<]([^\]>]*)[\]>]`)
+
+// orgProperty matches a line of a property drawer
+var orgProperty = regexp.MustCompile(`^\s*:([\w-]+):\s*(.*?)\s*$`)
+
+// orgTimestamp matches the date and the time (optional) of an Org timestamp
+var orgTimestamp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})(?:\s+[^\s\d]+)?(?:\s+(\d{1,2}:\d{2}))?`)
+
+// orgDate returns the Org timestamp of the date (without brackets)
+func orgDate(timestamp int64, withTime bool) string {
+	if withTime {
+		return time.Unix(timestamp, 0).Format(orgLayoutDateTime)
+	}
+	return time.Unix(timestamp, 0).Format(orgLayoutDate)
+}
+
+// parseOrgDate parses an Org timestamp (without brackets)
+func parseOrgDate(value string) (int64, error) {
+	match := orgTimestamp.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("ERR: the date %s is not a valid Org timestamp", value)
+	}
+	label, layout := match[1], layoutISO
+	if match[2] != "" {
+		label, layout = match[1]+" "+match[2], layoutISO+" 15:04"
+	}
+	date, err := time.ParseInLocation(layout, label, time.Local)
+	if err != nil {
+		return 0, err
+	}
+	return date.Unix(), nil
+}
+
+// orgEscape escapes the line of a note that could be read as Org syntax
+func orgEscape(line string) string {
+	if strings.HasPrefix(line, "*") || strings.HasPrefix(line, "#+") || strings.HasPrefix(line, ",") {
+		return "," + line
+	}
+	return line
+}
+
+// orgUnescape is the reverse function of orgEscape (the escaped line could be
+// indented)
+func orgUnescape(line string) string {
+	text := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(text, ",*") || strings.HasPrefix(text, ",#+") || strings.HasPrefix(text, ",,") {
+		return line[:len(line)-len(text)] + text[1:]
+	}
+	return line
+}
+
+// orgDedent removes the indentation that is common to all the non blank
+// lines of the text (the bodies are often indented as their headline).
+func orgDedent(text string) string {
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		size := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || size < indent {
+			indent = size
+		}
+	}
+	if indent <= 0 {
+		return text
+	}
+	for i, line := range lines {
+		if len(line) >= indent {
+			lines[i] = line[indent:]
+		} else {
+			lines[i] = strings.TrimSpace(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Org returns the Org-mode representation of the tasks of this journal that
+// satisfy the given filter, as an outline whose headlines are the tasks and
+// whose bodies are the contents of the notes. If title is not blank, it is
+// used as the title of the document.
+func (journal TaskJournal) Org(title string, taskFilter TaskFilter) string {
+	s := ""
+	if title != "" {
+		s += fmt.Sprintf("#+TITLE: %s\n", title)
+	}
+	s += fmt.Sprintf("#+TODO: %s %s | %s\n\n", orgKeywords[StatusTodo], orgKeywords[StatusDoing], orgKeywords[StatusDone])
+
+	walkTree(journal.TaskList, taskFilter, func(task Task, depth int) {
+		headline := fmt.Sprintf("%s %s ", strings.Repeat("*", depth+1), orgKeywords[task.Status])
+		if task.Priority != "" {
+			headline += fmt.Sprintf("[#%s] ", task.Priority)
+		}
+		headline += task.Description
+		if task.OnBoard {
+			headline += fmt.Sprintf(" :%s:", orgTagBoard)
+		}
+		s += headline + "\n"
+
+		planning := make([]string, 0, 2)
+		if completion := task.CompletionDate(); completion != 0 {
+			planning = append(planning, fmt.Sprintf("CLOSED: [%s]", orgDate(completion, true)))
+		}
+		if task.Due != 0 {
+			planning = append(planning, fmt.Sprintf("DEADLINE: <%s>", orgDate(task.Due, false)))
+		}
+		if len(planning) > 0 {
+			s += strings.Join(planning, " ") + "\n"
+		}
+		s += ":PROPERTIES:\n"
+		s += fmt.Sprintf(":GID:      %s\n", task.globalID())
+		s += fmt.Sprintf(":CREATED:  [%s]\n", orgDate(task.Timestamp, true))
+		s += ":END:\n"
+
+		body, err := journal.GetNoteContent(task.UIndex)
+		if err != nil || body == "" {
+			return
+		}
+		for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+			s += orgEscape(line) + "\n"
+		}
+	})
+	return s
+}
+
+// orgStatusMap returns the map from the Org keywords to the task status, as
+// defined by a #+TODO (or #+SEQ_TODO) line. The keywords after the "|" (or the
+// last keyword if there is no "|") are done states, the first keyword is the
+// todo state and the other keywords are doing states.
+func orgStatusMap(keywords string) map[string]TaskStatus {
+	words := strings.Fields(keywords)
+	done := len(words) - 1
+	for i, word := range words {
+		if word == "|" {
+			done = i
+		}
+	}
+	statusMap := make(map[string]TaskStatus)
+	for i, word := range words {
+		// The keywords could define a fast access key, e.g. TODO(t)
+		word = strings.SplitN(word, "(", 2)[0]
+		switch {
+		case word == "|":
+		case i >= done:
+			statusMap[word] = StatusDone
+		case i == 0:
+			statusMap[word] = StatusTodo
+		default:
+			statusMap[word] = StatusDoing
+		}
+	}
+	return statusMap
+}
+
+// ParseOrg parses the headlines of the Org text and returns the list of tasks
+// and the contents of their notes (the bodies of the headlines). The parent
+// relations are given by the nesting of the headlines. The UIndex of the tasks
+// are the order numbers of the headlines, to be remapped with
+// TaskJournal.ImportWithNotes. The TODO keywords are read from the #+TODO
+// lines (TODO DOING | DONE by default), and a headline with no keyword is a
+// todo task. The text before the first headline is ignored.
+func ParseOrg(text string) (TaskArray, map[TaskID]string, error) {
+	tasks := make(TaskArray, 0)
+	notes := make(map[TaskID]string)
+	statusMap := orgStatusMap("TODO DOING | DONE")
+
+	stack := make([]TaskID, 0) // UIDs of the ancestors, by level
+	var task *Task
+	inDrawer := false
+	inBody := false
+	appendTask := func() {
+		if task != nil {
+			tasks = append(tasks, *task)
+			task = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	nline := 0
+	for scanner.Scan() {
+		nline++
+		line := scanner.Text()
+		if task == nil || !inBody {
+			if upper := strings.ToUpper(line); strings.HasPrefix(upper, "#+TODO:") || strings.HasPrefix(upper, "#+SEQ_TODO:") {
+				statusMap = orgStatusMap(line[strings.Index(line, ":")+1:])
+				continue
+			}
+		}
+		if match := orgHeadline.FindStringSubmatch(line); match != nil {
+			appendTask()
+			level := len(match[1])
+			task = &Task{
+				UIndex:    TaskID(len(tasks) + 1),
+				Timestamp: timestamp(),
+				Status:    StatusTodo,
+				Priority:  match[3],
+			}
+			description := match[4]
+			if status, exists := statusMap[match[2]]; exists {
+				task.Status = status
+			} else if match[2] != "" {
+				description = strings.TrimSpace(match[2] + " " + description)
+			}
+			task.Description = description
+			for _, tag := range strings.Split(strings.Trim(match[5], ":"), ":") {
+				task.OnBoard = task.OnBoard || tag == orgTagBoard
+			}
+			if level > len(stack) {
+				level = len(stack) + 1
+			}
+			stack = append(stack[:level-1], task.UIndex)
+			if level > 1 {
+				task.ParentID = stack[level-2]
+			}
+			inDrawer, inBody = false, false
+			continue
+		}
+		if task == nil {
+			continue
+		}
+
+		var err error
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBody:
+			notes[task.UIndex] += orgUnescape(line) + "\n"
+		case inDrawer && trimmed == ":END:":
+			inDrawer = false
+		case inDrawer:
+			if match := orgProperty.FindStringSubmatch(line); match != nil {
+				switch strings.ToUpper(match[1]) {
+				case "GID":
+					task.setGlobalID(strings.TrimSpace(match[2]))
+				case "CREATED":
+					task.Timestamp, err = parseOrgDate(strings.Trim(match[2], "[]<>"))
+				}
+			}
+		case trimmed == ":PROPERTIES:":
+			inDrawer = true
+		case orgPlanning.MatchString(line) && !strings.HasPrefix(trimmed, ":"):
+			for _, match := range orgPlanning.FindAllStringSubmatch(line, -1) {
+				var date int64
+				date, err = parseOrgDate(match[2])
+				if err != nil {
+					break
+				}
+				switch match[1] {
+				case "CLOSED":
+					task.History = append(task.History, StatusChange{Status: StatusDone, Timestamp: date})
+				case "DEADLINE":
+					task.Due = date
+				}
+			}
+		case trimmed == "":
+		default:
+			inBody = true
+			notes[task.UIndex] += orgUnescape(line) + "\n"
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("ERR: line %d: %s", nline, err)
+		}
+	}
+	appendTask()
+
+	for uid, body := range notes {
+		notes[uid] = orgDedent(strings.TrimRight(body, "\n")) + "\n"
+	}
+	return tasks, notes, scanner.Err()
+}
@@ -0,0 +1,96 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrg(t *testing.T) {
+	tasks := createTreeTaskArray()
+	journal := TaskJournal{TaskList: tasks}
+	ptask, _ := journal.GetTask(13)
+	ptask.SetStatus(StatusDone)
+	ptask.OnBoard = true
+	ptask, _ = journal.GetTask(12)
+	ptask.Status = StatusDoing
+	ptask.Priority = "A"
+	ptask.initGlobalIndex()
+
+	text := journal.Org("Tree", TaskFilterAll)
+	printlog(text)
+
+	// The depth is given by the heading level, and the status by the keyword
+	lines := []string{
+		"#+TITLE: Tree\n",
+		"#+TODO: TODO DOING | DONE\n",
+		"\n* TODO A\n",
+		"\n** TODO B.1\n",
+		"\n*** DOING [#A] B.2.2\n",
+		"\n**** DONE B.2.2.1 :board:\nCLOSED: [",
+		"\n* TODO D\n",
+	}
+	for _, line := range lines {
+		if !strings.Contains(text, line) {
+			t.Errorf("The line %q is missing", line)
+		}
+	}
+
+	parsed, notes, err := ParseOrg(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Nb notes is %d (should be %d)", len(notes), 0)
+	}
+	byText := checkTreeRoundTrip(t, parsed)
+	done := byText("B.2.2.1")
+	if done.Status != StatusDone || !done.OnBoard || done.CompletionDate() == 0 {
+		t.Errorf("The status of %s is %s (should be %s)", "B.2.2.1", done.Status.Label(), "done")
+	}
+	doing := byText("B.2.2")
+	if doing.Status != StatusDoing || doing.Priority != "A" {
+		t.Errorf("The status of %s is %s (should be %s)", "B.2.2", doing.Status.Label(), "doing")
+	}
+	if doing.GUID != ptask.GUID {
+		t.Errorf("The GUID of %s is %s (should be %s)", "B.2.2", doing.GUID, ptask.GUID)
+	}
+}
+
+func TestParseOrg(t *testing.T) {
+	text := `#+TITLE: Plan
+#+TODO: TODO NEXT WAIT | DONE CANCELLED
+Some text before the outline
+* Home                     :house:
+** NEXT [#B] Paint the fence   :board:
+   SCHEDULED: <2024-01-05 Fri> DEADLINE: <2024-01-20 Sat>
+   Buy green paint
+
+     first
+   ,* not a headline
+*** CANCELLED Old idea
+* Plain heading
+`
+	tasks, notes, err := ParseOrg(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 4)
+	}
+	if tasks[1].ParentID != tasks[0].UIndex || tasks[2].ParentID != tasks[1].UIndex || tasks[3].ParentID != NoUID {
+		t.Errorf("The parent of %s is %d (should be %d)", tasks[1].Description, tasks[1].ParentID, tasks[0].UIndex)
+	}
+	task := tasks[1]
+	if task.Description != "Paint the fence" || task.Status != StatusDoing || task.Priority != "B" || !task.OnBoard {
+		t.Errorf("The task is %s (should be %s)", task.Description, "Paint the fence")
+	}
+	if datelabel(task.Due) != "2024-Jan-20" {
+		t.Errorf("The due date is %s (should be %s)", datelabel(task.Due), "2024-Jan-20")
+	}
+	if notes[task.UIndex] != "Buy green paint\n\n  first\n* not a headline\n" {
+		t.Errorf("The note is %q", notes[task.UIndex])
+	}
+	if tasks[2].Status != StatusDone || tasks[0].Status != StatusTodo || tasks[0].OnBoard {
+		t.Errorf("The status is %s (should be %s)", tasks[2].Status.Label(), "done")
+	}
+}
@@ -0,0 +1,117 @@
+package todo
+
+// Implementation of the edition of the configuration parameters
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// parameterValidators specifies the validation functions of the parameters
+// whose values need more than a type checking.
+var parameterValidators = map[string]func(value string) error{
+	"Indicators": validateIndicatorsTemplate,
+}
+
+// validateIndicatorsTemplate returns an error if the given template can not be
+// used as indicators template (syntax error or unknown data fields).
+func validateIndicatorsTemplate(value string) error {
+	tmpl, err := template.New("indicators").Parse(value)
+	if err != nil {
+		return fmt.Errorf("ERR: the template is not valid (%s)", err)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, taskIndicators{})
+	if err != nil {
+		return fmt.Errorf("ERR: the template is not valid (%s)", err)
+	}
+	return nil
+}
+
+// ParameterNames returns the sorted list of the names of the configuration
+// parameters (the keys usable with Get, Set and Unset).
+func ParameterNames() []string {
+	ptype := reflect.TypeOf(Parameters{})
+	names := make([]string, ptype.NumField())
+	for i := 0; i < ptype.NumField(); i++ {
+		names[i] = ptype.Field(i).Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// field returns the parameter field of the given key (case insensitive) and
+// its canonical name. Returns an error if the key is not a parameter name.
+func (parameters *Parameters) field(key string) (reflect.Value, string, error) {
+	for _, name := range ParameterNames() {
+		if strings.EqualFold(name, key) {
+			return reflect.ValueOf(parameters).Elem().FieldByName(name), name, nil
+		}
+	}
+	err := fmt.Errorf("ERR: the parameter %s is not defined (should be in: %s)", key, ParameterNames())
+	return reflect.Value{}, "", err
+}
+
+// Get returns a string representation of the value of the parameter key
+func (parameters Parameters) Get(key string) (string, error) {
+	field, _, err := parameters.field(key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", field.Interface()), nil
+}
+
+// Set sets the parameter key to the value given as a string. The value is
+// converted to the type of the parameter and validated before being set.
+func (parameters *Parameters) Set(key string, value string) error {
+	field, name, err := parameters.field(key)
+	if err != nil {
+		return err
+	}
+
+	if validator, exists := parameterValidators[name]; exists {
+		err = validator(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("ERR: the parameter %s requires a boolean value (not %s)", name, value)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("ERR: the parameter %s requires an integer value (not %s)", name, value)
+		}
+		if i < 0 {
+			return fmt.Errorf("ERR: the parameter %s requires a positive or zero value (not %s)", name, value)
+		}
+		field.SetInt(int64(i))
+	default:
+		return fmt.Errorf("ERR: the parameter %s can not be set from the command line", name)
+	}
+	return nil
+}
+
+// Unset resets the parameter key to its default value
+func (parameters *Parameters) Unset(key string) error {
+	field, name, err := parameters.field(key)
+	if err != nil {
+		return err
+	}
+	defaults := defaultConfig().Parameters
+	field.Set(reflect.ValueOf(defaults).FieldByName(name))
+	return nil
+}
@@ -0,0 +1,63 @@
+package todo
+
+import (
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	parameters := defaultConfig().Parameters
+
+	err := parameters.Set("prettyprint", "false")
+	if err != nil {
+		t.Error(err)
+	}
+	if parameters.PrettyPrint {
+		t.Errorf("PrettyPrint is %v (should be %v)", parameters.PrettyPrint, false)
+	}
+	value, err := parameters.Get("PrettyPrint")
+	if err != nil || value != "false" {
+		t.Errorf("PrettyPrint is %s (should be %s)", value, "false")
+	}
+
+	err = parameters.Set("WithColor", "maybe")
+	if err == nil {
+		t.Error("maybe is not a boolean value")
+	}
+
+	err = parameters.Set("Indicators", "[{{.Date}}:{{.Unknown}}]")
+	if err == nil {
+		t.Error("the template uses an unknown field")
+	}
+	if parameters.Indicators != DefaultIndicatorsTemplate {
+		t.Errorf("Indicators is %s (should be unchanged)", parameters.Indicators)
+	}
+
+	err = parameters.Set("Indicators", "[{{.Board}}]")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parameters.Set("WIPLimitDoing", "3")
+	if err != nil || parameters.WIPLimitDoing != 3 {
+		t.Errorf("WIPLimitDoing is %d (should be %d): %v", parameters.WIPLimitDoing, 3, err)
+	}
+	for _, key := range []string{"WIPLimitDoing", "ReviewAge"} {
+		err = parameters.Set(key, "-1")
+		if err == nil {
+			t.Errorf("%s can not be negative", key)
+		}
+	}
+	if parameters.WIPLimitDoing != 3 {
+		t.Errorf("WIPLimitDoing is %d (should be unchanged)", parameters.WIPLimitDoing)
+	}
+
+	err = parameters.Set("Unknown", "value")
+	if err == nil {
+		t.Error("Unknown is not a parameter")
+	}
+
+	err = parameters.Unset("indicators")
+	if err != nil || parameters.Indicators != DefaultIndicatorsTemplate {
+		t.Errorf("Indicators is %s (should be %s)", parameters.Indicators, DefaultIndicatorsTemplate)
+	}
+}
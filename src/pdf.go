@@ -0,0 +1,305 @@
+package todo
+
+// Implementation of a minimal PDF writer (PDF 1.4), used to print the reports
+// without any external program. The writer is limited to what the reports
+// need: the standard fonts Helvetica and Courier (that every PDF reader
+// provides, so no font is embedded), colored texts, lines and the geometric
+// shapes used to draw the status symbols.
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Dimensions of the pages (A4 portrait, in points)
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+)
+
+// pdfFont identifies one of the standard fonts used by the writer
+type pdfFont int
+
+// Enumeration of the fonts of the writer
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+	pdfItalic
+	pdfMono
+)
+
+var pdfFontNames = map[pdfFont]string{
+	pdfRegular: "Helvetica",
+	pdfBold:    "Helvetica-Bold",
+	pdfItalic:  "Helvetica-Oblique",
+	pdfMono:    "Courier",
+}
+
+// Widths (in thousandths of the font size) of the printable ASCII characters
+// (from space to tilde) of the fonts Helvetica and Helvetica-Bold. The
+// oblique font has the same metrics as the regular font, and Courier is a
+// monospaced font whose characters have a width of 600.
+var pdfHelveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var pdfHelveticaBoldWidths = []int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// pdfColor is a RGB color (components between 0 and 1)
+type pdfColor struct {
+	r, g, b float64
+}
+
+var (
+	pdfBlack = pdfColor{0, 0, 0}
+	pdfGrey  = pdfColor{0.45, 0.45, 0.45}
+)
+
+// pdfColors gives the RGB values of the colors of the terminal rendering
+var pdfColors = map[ColorIndex]pdfColor{
+	ColorRed:     {0.80, 0.15, 0.15},
+	ColorGreen:   {0.20, 0.60, 0.20},
+	ColorOrange:  {0.90, 0.55, 0.10},
+	ColorBlue:    {0.20, 0.40, 0.80},
+	ColorMagenta: {0.65, 0.25, 0.65},
+	ColorCyan:    {0.15, 0.60, 0.65},
+	ColorWhite:   {0.85, 0.85, 0.85},
+}
+
+// pdfWinAnsi gives the codes of the WinAnsi encoding of the characters that
+// are not in the Latin-1 range.
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// pdfEncode returns the text encoded in WinAnsi (the characters that can not
+// be encoded are replaced by a question mark).
+func pdfEncode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 32 && r < 127 || r >= 160 && r < 256:
+			encoded = append(encoded, byte(r))
+		case pdfWinAnsi[r] != 0:
+			encoded = append(encoded, pdfWinAnsi[r])
+		case r == '\t':
+			encoded = append(encoded, ' ')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// pdfString returns the PDF literal string of the text
+func pdfString(text string) string {
+	var builder strings.Builder
+	builder.WriteByte('(')
+	for _, c := range pdfEncode(text) {
+		if c == '(' || c == ')' || c == '\\' {
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(c)
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// textWidth returns the width (in points) of the text written with the font
+func textWidth(text string, font pdfFont, size float64) float64 {
+	width := 0
+	for _, c := range pdfEncode(text) {
+		switch {
+		case font == pdfMono:
+			width += 600
+		case c < 32 || c > 126:
+			width += 556
+		case font == pdfBold:
+			width += pdfHelveticaBoldWidths[c-32]
+		default:
+			width += pdfHelveticaWidths[c-32]
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// wrapText splits the text into lines whose width is at most the given width
+// (a word that is larger than the width is not split).
+func wrapText(text string, font pdfFont, size float64, width float64) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && textWidth(candidate, font, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitRunes splits the text into chunks of at most n characters (runes, so
+// that a multi-byte character is never split).
+func splitRunes(text string, n int) []string {
+	runes := []rune(text)
+	chunks := make([]string, 0, len(runes)/n+1)
+	for len(runes) > n {
+		chunks = append(chunks, string(runes[:n]))
+		runes = runes[n:]
+	}
+	return append(chunks, string(runes))
+}
+
+// pdfWriter creates a PDF document page by page. The current position y is
+// the top of the free space of the current page (measured from the bottom of
+// the page, as in the PDF coordinate system).
+type pdfWriter struct {
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	y      float64
+	footer string
+}
+
+// newPDFWriter creates a writer whose pages have the given footer (the page
+// number is added to the footer).
+func newPDFWriter(footer string) *pdfWriter {
+	return &pdfWriter{footer: footer}
+}
+
+// newPage starts a new page
+func (w *pdfWriter) newPage() {
+	w.page = new(bytes.Buffer)
+	w.pages = append(w.pages, w.page)
+	w.y = pdfPageHeight - pdfMargin
+}
+
+// reserve starts a new page if the height does not fit in the free space of
+// the current page, and moves the current position down of this height.
+// Returns the position of the reserved space.
+func (w *pdfWriter) reserve(height float64) float64 {
+	if w.page == nil || w.y-height < pdfMargin {
+		w.newPage()
+	}
+	y := w.y
+	w.y -= height
+	return y
+}
+
+// skip moves the current position down (without creating a new page)
+func (w *pdfWriter) skip(height float64) {
+	w.y -= height
+}
+
+func (w *pdfWriter) setColor(color pdfColor, stroke bool) {
+	operator := "rg"
+	if stroke {
+		operator = "RG"
+	}
+	fmt.Fprintf(w.page, "%.3f %.3f %.3f %s\n", color.r, color.g, color.b, operator)
+}
+
+// text writes the text with its baseline at the position (x, y)
+func (w *pdfWriter) text(x, y float64, text string, font pdfFont, size float64, color pdfColor) {
+	w.setColor(color, false)
+	fmt.Fprintf(w.page, "BT /F%d %.1f Tf %.2f %.2f Td %s Tj ET\n", font+1, size, x, y, pdfString(text))
+}
+
+// line draws a line from (x1, y1) to (x2, y2)
+func (w *pdfWriter) line(x1, y1, x2, y2 float64, color pdfColor, width float64) {
+	w.setColor(color, true)
+	fmt.Fprintf(w.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// circle draws a circle of center (x, y) and radius r, filled or not
+func (w *pdfWriter) circle(x, y, r float64, color pdfColor, fill bool) {
+	// The circle is approximated by four Bezier curves
+	const k = 0.5523
+	w.setColor(color, false)
+	w.setColor(color, true)
+	fmt.Fprintf(w.page, "1 w %.2f %.2f m\n", x+r, y)
+	fmt.Fprintf(w.page, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", x+r, y+k*r, x+k*r, y+r, x, y+r)
+	fmt.Fprintf(w.page, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", x-k*r, y+r, x-r, y+k*r, x-r, y)
+	fmt.Fprintf(w.page, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", x-r, y-k*r, x-k*r, y-r, x, y-r)
+	fmt.Fprintf(w.page, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", x+k*r, y-r, x+r, y-k*r, x+r, y)
+	if fill {
+		w.page.WriteString("f\n")
+	} else {
+		w.page.WriteString("S\n")
+	}
+}
+
+// triangle draws a filled triangle pointing to the right, of center (x, y)
+// and half height r
+func (w *pdfWriter) triangle(x, y, r float64, color pdfColor) {
+	w.setColor(color, false)
+	fmt.Fprintf(w.page, "%.2f %.2f m %.2f %.2f l %.2f %.2f l h f\n", x-r, y+r, x+r, y, x-r, y-r)
+}
+
+// bytes returns the PDF document
+func (w *pdfWriter) bytes() []byte {
+	if len(w.pages) == 0 {
+		w.newPage()
+	}
+	for i, page := range w.pages {
+		w.page = page
+		footer := fmt.Sprintf("%s - %d/%d", w.footer, i+1, len(w.pages))
+		w.text(pdfMargin, pdfMargin/2, footer, pdfItalic, 8, pdfGrey)
+	}
+
+	// The objects are numbered as follows: 1 catalog, 2 pages, 3 to 6 the
+	// fonts, and then a page and its content for each page.
+	objects := make([]string, 0)
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 7+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	fonts := ""
+	for font := pdfRegular; font <= pdfMono; font++ {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", pdfFontNames[font]))
+		fonts += fmt.Sprintf(" /F%d %d 0 R", font+1, 3+int(font))
+	}
+	for i, page := range w.pages {
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font <<%s >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, fonts, 8+2*i))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buffer.Len()
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buffer.Len()
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buffer.Bytes()
+}
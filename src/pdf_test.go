@@ -0,0 +1,90 @@
+package todo
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog"
+	width := textWidth("The quick brown fox", pdfRegular, 10)
+	lines := wrapText(text, pdfRegular, 10, width)
+	if len(lines) != 3 || lines[0] != "The quick brown fox" {
+		t.Errorf("The lines are %q", lines)
+	}
+	if textWidth("iiii", pdfMono, 10) != textWidth("MMMM", pdfMono, 10) {
+		t.Error("The Courier font should be monospaced")
+	}
+	chunks := splitRunes("àéîõü", 2)
+	if len(chunks) != 3 || chunks[0] != "àé" || chunks[2] != "ü" {
+		t.Errorf("The chunks are %q", chunks)
+	}
+	if pdfString("(a) é €") != "(\\(a\\) \xe9 \x80)" {
+		t.Errorf("The PDF string is %q", pdfString("(a) é €"))
+	}
+}
+
+func TestPDFReport(t *testing.T) {
+	journal := CreateTestJournal()
+	ptask, _ := journal.GetTask(2)
+	ptask.ParentID = 1
+	ptask.OnBoard = true
+	document := journal.PDFReport("Test report", ReportSections)
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4")) || !bytes.HasSuffix(document, []byte("%%EOF\n")) {
+		t.Fatal("The document is not a PDF document")
+	}
+	// The cross-reference table gives the offsets of the objects
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(document)
+	offset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(document[offset:], []byte("xref")) {
+		t.Errorf("The xref offset %d is not valid", offset)
+	}
+	for i, match := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(document, -1) {
+		offset, _ := strconv.Atoi(string(match[1]))
+		if !bytes.HasPrefix(document[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Errorf("The offset of the object %d is not valid", i+1)
+		}
+	}
+	// A title page and a page per section
+	if !bytes.Contains(document, []byte("/Count 4")) {
+		t.Error("The document should have 4 pages")
+	}
+	if !bytes.Contains(document, []byte(pdfString(journal.TaskList[3].Description))) {
+		t.Error("The document should contain the description of the tasks")
+	}
+}
+
+func TestNoteBlocks(t *testing.T) {
+	content := `Title
+=====
+
+A paragraph
+on two lines.
+
+- first item
+- second item
+  continued
+
+Example::
+
+    $ todo list
+
+The end.`
+	blocks := noteBlocks(content)
+	kinds := []noteBlockKind{blockTitle, blockParagraph, blockBullet, blockBullet, blockParagraph, blockLiteral, blockParagraph}
+	if len(blocks) != len(kinds) {
+		t.Fatalf("Nb blocks is %d (should be %d): %v", len(blocks), len(kinds), blocks)
+	}
+	for i, block := range blocks {
+		if block.kind != kinds[i] {
+			t.Errorf("The kind of the block %d is %d (should be %d)", i, block.kind, kinds[i])
+		}
+	}
+	if len(blocks[3].lines) != 2 || blocks[4].lines[0] != "Example:" || blocks[5].lines[0] != "$ todo list" {
+		t.Errorf("The blocks are %q", blocks)
+	}
+}
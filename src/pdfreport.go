@@ -0,0 +1,355 @@
+package todo
+
+// Implementation of the PDF report of a journal (see PDFReport)
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Layout of the report (sizes in points)
+const (
+	pdfTextSize    = 10.0
+	pdfSmallSize   = 8.0
+	pdfMonoSize    = 8.5
+	pdfLineHeight  = 13.0
+	pdfTreeIndent  = 16.0
+	pdfSymbolSize  = 3.5
+	pdfSectionSize = 16.0
+)
+
+// drawStatus draws the symbol of the status (the same symbols as in the
+// terminal rendering) centered on the position (x, y)
+func (w *pdfWriter) drawStatus(x, y float64, status TaskStatus) {
+	color := pdfColors[taskStatusColors[status]]
+	switch status {
+	case StatusTodo:
+		w.circle(x, y, pdfSymbolSize, color, false)
+	case StatusDoing:
+		w.triangle(x, y, pdfSymbolSize, color)
+	default:
+		w.circle(x, y, pdfSymbolSize, color, true)
+	}
+}
+
+// heading writes the title of a section (or of a sub-section if small)
+func (w *pdfWriter) heading(title string, small bool) {
+	size := pdfSectionSize
+	if small {
+		size = pdfTextSize + 2
+	}
+	y := w.reserve(2*size) - size
+	w.text(pdfMargin, y, title, pdfBold, size, pdfBlack)
+	if !small {
+		w.line(pdfMargin, y-5, pdfPageWidth-pdfMargin, y-5, pdfGrey, 0.5)
+	}
+}
+
+// paragraph writes the text wrapped between the position x and the right
+// margin of the page
+func (w *pdfWriter) paragraph(x float64, text string, font pdfFont, size float64) {
+	for _, line := range wrapText(text, font, size, pdfPageWidth-pdfMargin-x) {
+		y := w.reserve(pdfLineHeight) - size
+		w.text(x, y, line, font, size, pdfBlack)
+	}
+}
+
+// drawTask writes the task on one row (on several lines if the description is
+// long): the status symbol, the UID, the description and, on the right, the
+// date and the indicators of the note and the board. The row is indented
+// according to the depth of the task in the tree.
+func (w *pdfWriter) drawTask(task Task, depth int) {
+	x := pdfMargin + float64(depth)*pdfTreeIndent
+	right := pdfPageWidth - pdfMargin
+
+	info := datelabel(task.Timestamp)
+	if task.NotePath != "" {
+		info += " - note"
+	}
+	if task.OnBoard {
+		info += " - board"
+	}
+	uid := fmt.Sprintf("%d", task.UIndex)
+	xtext := x + 2*pdfSymbolSize + 6 + textWidth("0000", pdfBold, pdfTextSize)
+	width := right - xtext - textWidth(info, pdfRegular, pdfSmallSize) - 12
+	lines := wrapText(task.Description, pdfRegular, pdfTextSize, width)
+
+	top := w.reserve(pdfLineHeight * float64(len(lines)))
+	y := top - pdfTextSize
+	if depth > 0 {
+		// The connector to the parent task, as the └─ of the text tree
+		xparent := x - pdfTreeIndent + pdfSymbolSize
+		w.line(xparent, top+2, xparent, y+3, pdfGrey, 0.6)
+		w.line(xparent, y+3, x, y+3, pdfGrey, 0.6)
+	}
+	w.drawStatus(x+pdfSymbolSize, y+3, task.Status)
+	w.text(x+2*pdfSymbolSize+6, y, uid, pdfBold, pdfTextSize, pdfBlack)
+	w.text(right-textWidth(info, pdfRegular, pdfSmallSize), y, info, pdfRegular, pdfSmallSize, pdfGrey)
+	for i, line := range lines {
+		w.text(xtext, y-float64(i)*pdfLineHeight, line, pdfRegular, pdfTextSize, pdfBlack)
+	}
+}
+
+// drawLegend writes the legend of the status symbols
+func (w *pdfWriter) drawLegend() {
+	y := w.reserve(2*pdfLineHeight) - pdfLineHeight - pdfSmallSize
+	x := pdfMargin
+	for status := StatusStart; status <= StatusEnd; status++ {
+		w.drawStatus(x+pdfSymbolSize, y+3, status)
+		w.text(x+2*pdfSymbolSize+4, y, status.Label(), pdfRegular, pdfSmallSize, pdfGrey)
+		x += 60
+	}
+}
+
+// drawTasks writes the tasks that satisfy the filter, as a tree or as a list
+func (w *pdfWriter) drawTasks(tasks TaskArray, taskFilter TaskFilter, tree bool) {
+	ntasks := 0
+	visit := func(task Task, depth int) {
+		if depth == 0 && ntasks > 0 && tree {
+			w.skip(pdfLineHeight / 2)
+		}
+		w.drawTask(task, depth)
+		ntasks++
+	}
+	if tree {
+		walkTree(tasks, taskFilter, visit)
+	} else {
+		for _, task := range tasks {
+			if taskFilter(task) {
+				visit(task, 0)
+			}
+		}
+	}
+	if ntasks == 0 {
+		w.paragraph(pdfMargin, notasks, pdfItalic, pdfTextSize)
+		return
+	}
+	w.drawLegend()
+}
+
+// drawTitlePage writes the title page of the report: the title, the date and
+// a summary of the tasks by status.
+func (w *pdfWriter) drawTitlePage(title string, tasks TaskArray) {
+	w.newPage()
+	w.y = pdfPageHeight * 2 / 3
+	y := w.reserve(40) - 24
+	w.text(pdfMargin, y, title, pdfBold, 24, pdfBlack)
+	w.line(pdfMargin, y-10, pdfPageWidth-pdfMargin, y-10, pdfGrey, 1)
+	dlabel := time.Now().Format("Monday, January 2, 2006")
+	y = w.reserve(30) - 12
+	w.text(pdfMargin, y, dlabel, pdfItalic, 12, pdfGrey)
+
+	w.skip(20)
+	for status := StatusStart; status <= StatusEnd; status++ {
+		count := len(tasks.getTasksWithFilter(func(task Task) bool { return task.Status == status }))
+		y = w.reserve(20) - 12
+		w.drawStatus(pdfMargin+pdfSymbolSize, y+4, status)
+		w.text(pdfMargin+20, y, fmt.Sprintf("%d %s", count, status.Label()), pdfRegular, 12, pdfBlack)
+	}
+	y = w.reserve(20) - 12
+	w.text(pdfMargin+20, y, fmt.Sprintf("%d tasks", len(tasks)), pdfBold, 12, pdfBlack)
+}
+
+// noteBlockKind is the type of a block of text of a note
+type noteBlockKind int
+
+const (
+	blockParagraph noteBlockKind = iota
+	blockTitle
+	blockBullet
+	blockLiteral
+)
+
+// noteBlock is a block of text of a note (a paragraph, a title, an item of a
+// list or a literal block)
+type noteBlock struct {
+	kind  noteBlockKind
+	lines []string
+}
+
+// noteBullet matches an item of a bulleted or enumerated list
+var noteBullet = regexp.MustCompile(`^\s*([-*+•]|\d+[.)]|#\.)\s+(.*)$`)
+
+// isUnderline returns true if the line could be the underline (or the
+// overline) of a reStructuredText title, i.e. a repeated punctuation character
+func isUnderline(line string) bool {
+	if line == "" || !strings.ContainsRune(`=-~^"'*#+`, rune(line[0])) {
+		return false
+	}
+	return strings.Trim(line, line[:1]) == ""
+}
+
+// noteIndent returns the size of the indentation of the line
+func noteIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// inlineText removes the inline markup of reStructuredText (literals, strong
+// emphasis) from the text
+func inlineText(text string) string {
+	return strings.NewReplacer("``", "", "**", "").Replace(text)
+}
+
+// noteBlocks splits the content of a note (in reStructuredText) into blocks
+// of text. Only the basic structures are recognized (titles, paragraphs,
+// lists and literal blocks introduced by "::"), the other markups are written
+// as plain text.
+func noteBlocks(content string) []noteBlock {
+	lines := strings.Split(strings.ReplaceAll(content, "\t", "    "), "\n")
+	blocks := make([]noteBlock, 0)
+	var current *noteBlock
+	flush := func() {
+		if current != nil {
+			blocks = append(blocks, *current)
+			current = nil
+		}
+	}
+	literal := false // true if the next indented block is a literal block
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " ")
+		if line == "" {
+			flush()
+			continue
+		}
+		if literal && noteIndent(line) > 0 {
+			// The literal block ends with the first line that is not indented
+			flush()
+			block := noteBlock{kind: blockLiteral}
+			for ; i < len(lines) && (strings.TrimSpace(lines[i]) == "" || noteIndent(lines[i]) > 0); i++ {
+				block.lines = append(block.lines, strings.TrimRight(lines[i], " "))
+			}
+			i--
+			for len(block.lines) > 0 && block.lines[len(block.lines)-1] == "" {
+				block.lines = block.lines[:len(block.lines)-1]
+			}
+			indent := -1
+			for _, l := range block.lines {
+				if l != "" && (indent < 0 || noteIndent(l) < indent) {
+					indent = noteIndent(l)
+				}
+			}
+			for j, l := range block.lines {
+				if len(l) >= indent {
+					block.lines[j] = l[indent:]
+				}
+			}
+			blocks = append(blocks, block)
+			literal = false
+			continue
+		}
+		literal = false
+		if isUnderline(line) && current == nil {
+			// Overline of a title
+			continue
+		}
+		if i+1 < len(lines) && isUnderline(strings.TrimSpace(lines[i+1])) &&
+			len(strings.TrimSpace(lines[i+1])) >= len(strings.TrimSpace(line)) && current == nil {
+			blocks = append(blocks, noteBlock{kind: blockTitle, lines: []string{strings.TrimSpace(line)}})
+			i++
+			continue
+		}
+		if match := noteBullet.FindStringSubmatch(line); match != nil {
+			flush()
+			current = &noteBlock{kind: blockBullet, lines: []string{match[2]}}
+		} else if current == nil {
+			current = &noteBlock{kind: blockParagraph, lines: []string{strings.TrimSpace(line)}}
+		} else {
+			current.lines = append(current.lines, strings.TrimSpace(line))
+		}
+		if strings.HasSuffix(line, "::") {
+			literal = true
+			last := &current.lines[len(current.lines)-1]
+			// "text::" is written "text:", and "text ::" is written "text"
+			*last = strings.TrimSuffix(*last, ":")
+			if *last == ":" || strings.HasSuffix(*last, " :") {
+				*last = strings.TrimSpace(strings.TrimSuffix(*last, ":"))
+			}
+			flush()
+		}
+	}
+	flush()
+	return blocks
+}
+
+// drawNote writes the content of a note, formatted according to its blocks
+func (w *pdfWriter) drawNote(content string) {
+	monoWidth := textWidth("0", pdfMono, pdfMonoSize)
+	maxChars := int((pdfPageWidth - 2*pdfMargin - 12) / monoWidth)
+	for _, block := range noteBlocks(content) {
+		text := inlineText(strings.TrimSpace(strings.Join(block.lines, " ")))
+		switch block.kind {
+		case blockTitle:
+			w.skip(4)
+			w.paragraph(pdfMargin, text, pdfBold, pdfTextSize)
+		case blockBullet:
+			lines := wrapText(text, pdfRegular, pdfTextSize, pdfPageWidth-2*pdfMargin-14)
+			for i, line := range lines {
+				y := w.reserve(pdfLineHeight) - pdfTextSize
+				if i == 0 {
+					w.text(pdfMargin+4, y, "•", pdfRegular, pdfTextSize, pdfBlack)
+				}
+				w.text(pdfMargin+14, y, line, pdfRegular, pdfTextSize, pdfBlack)
+			}
+		case blockLiteral:
+			for _, line := range block.lines {
+				for _, chunk := range splitRunes(line, maxChars) {
+					y := w.reserve(pdfLineHeight-2) - pdfMonoSize
+					w.text(pdfMargin+12, y, chunk, pdfMono, pdfMonoSize, pdfBlack)
+				}
+			}
+		default:
+			if text != "" {
+				w.paragraph(pdfMargin, text, pdfRegular, pdfTextSize)
+			}
+		}
+		w.skip(4)
+	}
+}
+
+// PDFReport returns a PDF document made of a title page and the given sections
+// (ReportSections for a complete report). The tasks are written with the
+// symbols of their status, and the notes are formatted from their
+// reStructuredText content.
+func (journal TaskJournal) PDFReport(title string, sections []ReportSection) []byte {
+	w := newPDFWriter(title)
+	w.drawTitlePage(title, journal.TaskList)
+	for _, section := range sections {
+		w.newPage()
+		w.heading(section.Title(), false)
+		switch section {
+		case SectionTree:
+			w.drawTasks(journal.TaskList, TaskFilterAll, true)
+		case SectionBoard:
+			w.drawTasks(journal.TaskList, TaskFilterOnBoard, false)
+		case SectionNotes:
+			journal.drawNotes(w)
+		default:
+			w.drawTasks(journal.TaskList, TaskFilterAll, false)
+		}
+	}
+	return w.bytes()
+}
+
+// drawNotes writes the notes of the tasks of this journal
+func (journal TaskJournal) drawNotes(w *pdfWriter) {
+	nnotes := 0
+	for _, task := range journal.TaskList {
+		if task.NotePath == "" {
+			continue
+		}
+		content, err := journal.GetNoteContent(task.UIndex)
+		if err != nil {
+			content = err.Error()
+		}
+		w.skip(6)
+		w.heading(fmt.Sprintf("%d - %s", task.UIndex, task.Description), true)
+		w.drawNote(content)
+		nnotes++
+	}
+	if nnotes == 0 {
+		w.paragraph(pdfMargin, "No notes", pdfItalic, pdfTextSize)
+	}
+}
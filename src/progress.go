@@ -0,0 +1,131 @@
+package todo
+
+// Implementation of the progress of the parent tasks (the projects), computed
+// from the status of their descendants, and of the derivation of the status
+// of the parent tasks from the status of their children.
+
+import (
+	"fmt"
+)
+
+// Progress is the completion of the descendants of a task
+type Progress struct {
+	Done  int // Number of descendants that are done
+	Total int // Number of descendants
+}
+
+// Percent returns the percentage of the descendants that are done
+func (progress Progress) Percent() int {
+	if progress.Total == 0 {
+		return 0
+	}
+	return 100 * progress.Done / progress.Total
+}
+
+// String returns a string representation of this progress, e.g. [3/5], and
+// an empty string if the task has no descendant.
+func (progress Progress) String() string {
+	if progress.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%d/%d]", progress.Done, progress.Total)
+}
+
+// progress returns the progress of each task of this array that has children
+func (tasks TaskArray) progress() map[TaskID]Progress {
+	tree := make(treeMap, 0)
+	tree.initialize(tasks)
+	progresses := make(map[TaskID]Progress)
+	for _, task := range tasks {
+		if len(tree[task.UIndex]) == 0 {
+			continue
+		}
+		var progress Progress
+		for _, uindex := range tree.descendants(task.UIndex) {
+			descendant, err := tasks.getTask(uindex)
+			if err != nil {
+				continue
+			}
+			progress.Total++
+			if descendant.Status == StatusDone {
+				progress.Done++
+			}
+		}
+		progresses[task.UIndex] = progress
+	}
+	return progresses
+}
+
+// withProgress returns the taskString function that renders the tasks with
+// their progress (computed from the given array of tasks)
+func (tasks TaskArray) withProgress(taskString func(Task) string) func(Task) string {
+	progresses := tasks.progress()
+	return func(task Task) string {
+		task.progress = progresses[task.UIndex]
+		return taskString(task)
+	}
+}
+
+// derivedStatus returns the status of a parent task derived from the status
+// of its children: done when all the children are done, doing when a child is
+// doing. A done parent whose children are not all done is doing (or todo if
+// no child has been started), and the status is unchanged otherwise.
+func derivedStatus(status TaskStatus, children TaskArray) TaskStatus {
+	ndone, ndoing := 0, 0
+	for _, child := range children {
+		switch child.Status {
+		case StatusDone:
+			ndone++
+		case StatusDoing:
+			ndoing++
+		}
+	}
+	switch {
+	case ndone == len(children):
+		return StatusDone
+	case ndoing > 0:
+		return StatusDoing
+	case status == StatusDone && ndone > 0:
+		return StatusDoing
+	case status == StatusDone:
+		return StatusTodo
+	}
+	return status
+}
+
+// UpdateParentStatus derives the status of the parent tasks of this journal
+// from the status of their children (see derivedStatus), from the leaves up
+// to the root tasks. Returns the UIDs of the tasks whose status has changed.
+func (journal *TaskJournal) UpdateParentStatus() TaskIDArray {
+	tree := make(treeMap, 0)
+	tree.initialize(journal.TaskList)
+	changed := make(TaskIDArray, 0)
+	visited := make(map[TaskID]bool)
+	var update func(uindex TaskID)
+	update = func(uindex TaskID) {
+		if visited[uindex] {
+			// Protection against the cycles of parent relations
+			return
+		}
+		visited[uindex] = true
+		children := make(TaskArray, 0)
+		for _, childID := range tree[uindex] {
+			update(childID)
+			if child, err := journal.GetTask(childID); err == nil {
+				children = append(children, *child)
+			}
+		}
+		task, err := journal.GetTask(uindex)
+		if err != nil || len(children) == 0 {
+			return
+		}
+		if status := derivedStatus(task.Status, children); status != task.Status {
+			task.SetStatus(status)
+			changed = append(changed, uindex)
+		}
+	}
+	for _, task := range journal.TaskList {
+		update(task.UIndex)
+	}
+	return changed
+}
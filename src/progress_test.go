@@ -0,0 +1,104 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	tasks := createTreeTaskArray()
+	for _, uindex := range []TaskID{7, 11, 13} { // B.1, B.2.1, B.2.2.1
+		task, _ := tasks.getTask(uindex)
+		task.Status = StatusDone
+	}
+	progresses := tasks.progress()
+
+	expected := map[TaskID]Progress{
+		31: {Done: 3, Total: 5}, // B
+		8:  {Done: 2, Total: 3}, // B.2
+		12: {Done: 1, Total: 1}, // B.2.2
+		30: {Done: 0, Total: 3}, // A
+	}
+	for uindex, progress := range expected {
+		if progresses[uindex] != progress {
+			t.Errorf("The progress of %d is %v (should be %v)", uindex, progresses[uindex], progress)
+		}
+	}
+	if _, exists := progresses[14]; exists {
+		t.Error("The task 14 has no children and should have no progress")
+	}
+	if progresses[31].String() != "[3/5]" || progresses[31].Percent() != 60 {
+		t.Errorf("The progress of 31 is %s %d%% (should be [3/5] 60%%)", progresses[31], progresses[31].Percent())
+	}
+
+	tree := TreeString(tasks)
+	printlog(tree)
+	if !strings.Contains(tree, ": B [3/5] 60%\n") {
+		t.Error("The progress of B is missing in the tree")
+	}
+	if strings.Contains(tree, ": D [") {
+		t.Error("The task D has no children and should have no progress")
+	}
+}
+
+func TestProgressIndicators(t *testing.T) {
+	err := validateIndicatorsTemplate("[{{.Date}}]{{.Progress}}{{.Percent}}")
+	if err != nil {
+		t.Error(err)
+	}
+	task := CreateTestTask(1, "Project")
+	task.progress = Progress{Done: 1, Total: 4}
+	config, _ := GetConfig()
+	indicators := config.Parameters.Indicators
+	defer func() { config.Parameters.Indicators = indicators }()
+	config.Parameters.Indicators = "{{.Progress}} {{.Percent}}"
+	if result := task.getTaskIndicators(); result != "[1/4] 25%" {
+		t.Errorf("The indicators are %s (should be %s)", result, "[1/4] 25%")
+	}
+}
+
+func TestUpdateParentStatus(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	setStatus := func(uindex TaskID, status TaskStatus) {
+		task, _ := journal.GetTask(uindex)
+		task.Status = status
+	}
+	status := func(uindex TaskID) TaskStatus {
+		task, _ := journal.GetTask(uindex)
+		return task.Status
+	}
+
+	// B.2.2.1 doing: B.2.2, B.2 and B are doing
+	setStatus(13, StatusDoing)
+	changed := journal.UpdateParentStatus()
+	if len(changed) != 3 || status(12) != StatusDoing || status(8) != StatusDoing || status(31) != StatusDoing {
+		t.Errorf("The changed tasks are %v (should be 12, 8, 31 doing)", changed)
+	}
+
+	// All the children of B done: B is done
+	for _, uindex := range []TaskID{7, 11, 13} {
+		setStatus(uindex, StatusDone)
+	}
+	journal.UpdateParentStatus()
+	if status(31) != StatusDone || status(8) != StatusDone {
+		t.Errorf("The status of B is %s (should be done)", status(31).Label())
+	}
+	parent, _ := journal.GetTask(31)
+	if parent.StatusDate(StatusDone) == 0 {
+		t.Error("The change of status should be recorded in the history")
+	}
+
+	// A new todo child of B: B is doing again (some children are done)
+	journal.TaskList = append(journal.TaskList, Task{UIndex: 40, Description: "B.3", ParentID: 31})
+	journal.UpdateParentStatus()
+	if status(31) != StatusDoing {
+		t.Errorf("The status of B is %s (should be doing)", status(31).Label())
+	}
+
+	// The status of A (todo children) is unchanged
+	setStatus(30, StatusDoing)
+	journal.UpdateParentStatus()
+	if status(30) != StatusDoing {
+		t.Errorf("The status of A is %s (should be unchanged)", status(30).Label())
+	}
+}
@@ -0,0 +1,194 @@
+package todo
+
+// Implementation of the items of the review of a journal. The review walks
+// through the tasks that need a decision: the doing tasks that have not
+// changed for a long time, the old todo tasks, the tasks on board, the done
+// tasks that are not archived, and the orphaned notes (the note files that no
+// task refers to). The decisions themselves are taken and applied by the
+// command line interface.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultReviewAge is the default age (in days) from which a task is stale
+const DefaultReviewAge = 14
+
+// ReviewKind is the reason why an item is reviewed
+type ReviewKind int
+
+// Enumeration of the kinds of review items
+const (
+	ReviewDoing ReviewKind = iota
+	ReviewTodo
+	ReviewBoard
+	ReviewDone
+	ReviewNote
+)
+
+var reviewTitles = map[ReviewKind]string{
+	ReviewDoing: "Stale doing task",
+	ReviewTodo:  "Old todo task",
+	ReviewBoard: "Task on board",
+	ReviewDone:  "Done task not archived",
+	ReviewNote:  "Orphaned note",
+}
+
+// Title returns the title of the kind of review items
+func (kind ReviewKind) Title() string {
+	return reviewTitles[kind]
+}
+
+// ReviewAction is a decision taken on a review item
+type ReviewAction int
+
+// Enumeration of the review actions
+const (
+	ActionKeep ReviewAction = iota
+	ActionNext
+	ActionArchive
+	ActionUnboard
+	ActionDelete
+)
+
+var reviewActionLabels = map[ReviewAction]string{
+	ActionKeep:    "keep",
+	ActionNext:    "next status",
+	ActionArchive: "archive",
+	ActionUnboard: "remove from board",
+	ActionDelete:  "delete",
+}
+
+// Label returns a string representation of the action
+func (action ReviewAction) Label() string {
+	return reviewActionLabels[action]
+}
+
+// ReviewItem is a task (or an orphaned note) to review
+type ReviewItem struct {
+	Kind     ReviewKind
+	Task     Task   // The task to review (undefined for an orphaned note)
+	NotePath string // The absolute path of the orphaned note
+	Age      int    // The age (in days) of the last change of the task
+}
+
+// Actions returns the actions that can be applied to this item, the first one
+// being the default action.
+func (item ReviewItem) Actions() []ReviewAction {
+	if item.Kind == ReviewNote {
+		return []ReviewAction{ActionKeep, ActionDelete}
+	}
+	actions := []ReviewAction{ActionKeep}
+	if item.Task.Status != StatusEnd {
+		actions = append(actions, ActionNext)
+	}
+	actions = append(actions, ActionArchive)
+	if item.Task.OnBoard {
+		actions = append(actions, ActionUnboard)
+	}
+	return append(actions, ActionDelete)
+}
+
+// String returns a string representation of this item
+func (item ReviewItem) String() string {
+	switch item.Kind {
+	case ReviewNote:
+		return fmt.Sprintf("%s: %s", item.Kind.Title(), item.NotePath)
+	case ReviewDoing:
+		return fmt.Sprintf("%s (doing for %d days):\n%s", item.Kind.Title(), item.Age, item.Task.String())
+	case ReviewTodo:
+		return fmt.Sprintf("%s (created %d days ago):\n%s", item.Kind.Title(), item.Age, item.Task.String())
+	}
+	return fmt.Sprintf("%s:\n%s", item.Kind.Title(), item.Task.String())
+}
+
+// ReviewItems returns the items to review in this journal at the date now: the
+// doing tasks that have not changed for more than maxAge days, the todo tasks
+// created more than maxAge days ago, the other tasks on board, the done tasks,
+// and the note files of the notebook that are not referred by a task of this
+// journal or of its archive. A task is reviewed once, for the first of these
+// reasons. If maxAge is not positive, the DefaultReviewAge is used.
+func (journal TaskJournal) ReviewItems(archive TaskJournal, now int64, maxAge int) ([]ReviewItem, error) {
+	if maxAge <= 0 {
+		maxAge = DefaultReviewAge
+	}
+	age := func(date int64) int { return int((now - date) / (24 * 60 * 60)) }
+
+	groups := make(map[ReviewKind][]ReviewItem)
+	for _, task := range journal.TaskList {
+		lastChange := task.StatusDate(task.Status)
+		if lastChange == 0 || task.Status == StatusTodo {
+			lastChange = task.Timestamp
+		}
+		item := ReviewItem{Task: task, Age: age(lastChange)}
+		switch {
+		case task.Status == StatusDone:
+			item.Kind = ReviewDone
+		case task.Status == StatusDoing && item.Age > maxAge:
+			item.Kind = ReviewDoing
+		case task.Status == StatusTodo && item.Age > maxAge:
+			item.Kind = ReviewTodo
+		case task.OnBoard:
+			item.Kind = ReviewBoard
+		default:
+			continue
+		}
+		groups[item.Kind] = append(groups[item.Kind], item)
+	}
+
+	notes, err := journal.orphanedNotes(archive)
+	if err != nil {
+		return nil, err
+	}
+	for _, notepath := range notes {
+		groups[ReviewNote] = append(groups[ReviewNote], ReviewItem{Kind: ReviewNote, NotePath: notepath})
+	}
+
+	items := make([]ReviewItem, 0)
+	for _, kind := range []ReviewKind{ReviewDoing, ReviewTodo, ReviewBoard, ReviewDone, ReviewNote} {
+		items = append(items, groups[kind]...)
+	}
+	return items, nil
+}
+
+// orphanedNotes returns the absolute paths of the files of the notebook of
+// this journal that are not the note of a task of this journal or of its
+// archive (sorted by name).
+func (journal TaskJournal) orphanedNotes(archive TaskJournal) ([]string, error) {
+	if journal.File() == "" {
+		return nil, nil
+	}
+	notebook := filepath.Join(filepath.Dir(journal.File()), NotebookDirname)
+	entries, err := os.ReadDir(notebook)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, task := range journal.TaskList {
+		if task.NotePath != "" {
+			used[journal.absNotePath(task)] = true
+		}
+	}
+	for _, task := range archive.TaskList {
+		if task.NotePath != "" {
+			used[journal.absNotePath(task)] = true
+		}
+	}
+
+	notes := make([]string, 0)
+	for _, entry := range entries {
+		notepath := filepath.Join(notebook, entry.Name())
+		if !entry.IsDir() && !used[notepath] {
+			notes = append(notes, notepath)
+		}
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
@@ -0,0 +1,64 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReviewItems(t *testing.T) {
+	now := timestamp()
+	day := int64(24 * 60 * 60)
+	journal := CreateTestJournal()
+	journal.filepath = filepath.Join(t.TempDir(), JournalFilename)
+	for i := range journal.TaskList {
+		journal.TaskList[i].Timestamp = now - 30*day
+		journal.TaskList[i].History = nil
+	}
+	journal.TaskList[0].Status = StatusDoing // stale doing task
+	journal.TaskList[0].History = StatusHistory{{Status: StatusDoing, Timestamp: now - 20*day}}
+	journal.TaskList[1].Timestamp = now - day // recent todo task on board
+	journal.TaskList[1].OnBoard = true
+	journal.TaskList[2].Status = StatusDone
+	journal.TaskList[2].History = StatusHistory{{Status: StatusDone, Timestamp: now - day}}
+	journal.TaskList[3].NotePath = filepath.Join(NotebookDirname, "4.rst")
+
+	notebook := filepath.Join(filepath.Dir(journal.File()), NotebookDirname)
+	os.MkdirAll(notebook, 0755)
+	for _, name := range []string{"4.rst", "5.rst"} {
+		WriteBytes(filepath.Join(notebook, name), []byte("note"))
+	}
+
+	items, err := journal.ReviewItems(TaskJournal{}, now, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		kind ReviewKind
+		uid  TaskID
+	}{{ReviewDoing, 1}, {ReviewTodo, 4}, {ReviewBoard, 2}, {ReviewDone, 3}, {ReviewNote, NoUID}}
+	if len(items) != len(expected) {
+		t.Fatalf("Nb items is %d (should be %d)", len(items), len(expected))
+	}
+	for i, item := range items {
+		if item.Kind != expected[i].kind || item.Task.UIndex != expected[i].uid {
+			t.Errorf("The item %d is %s %d (should be %s %d)", i, item.Kind.Title(), item.Task.UIndex,
+				expected[i].kind.Title(), expected[i].uid)
+		}
+	}
+	if items[0].Age != 20 {
+		t.Errorf("The age is %d (should be %d)", items[0].Age, 20)
+	}
+	if filepath.Base(items[4].NotePath) != "5.rst" {
+		t.Errorf("The orphaned note is %s (should be %s)", items[4].NotePath, "5.rst")
+	}
+	if len(items[3].Actions()) != 3 {
+		t.Errorf("The done task has the actions %v (should be keep, archive, delete)", items[3].Actions())
+	}
+
+	// With a larger age, the doing and todo tasks are not stale
+	items, _ = journal.ReviewItems(TaskJournal{}, now, 40)
+	if len(items) != 3 {
+		t.Errorf("Nb items is %d (should be %d)", len(items), 3)
+	}
+}
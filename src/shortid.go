@@ -0,0 +1,139 @@
+package todo
+
+// Implementation of the short IDs of the tasks. The usage indeces (UID) are
+// recycled, and the global indeces (GID) are too long to be typed, then each
+// task is also given a short ID, e.g. t1a, that is stable (the task keeps its
+// short ID when it is archived and restored) and never reused in a context.
+// The short ID is the base 36 representation of a counter of the journal with
+// the prefix ShortIDPrefix (so that it can not be confused with a UID).
+//
+// The short IDs can be used on the command line wherever a TaskID is expected:
+// the parser of TaskID encodes the counter of the short ID in a TaskID value
+// with the bit shortIDFlag, and this value is resolved to the UID of the task
+// by the journal (see TaskJournal.Resolve).
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShortIDPrefix is the prefix of the short IDs
+const ShortIDPrefix = "t"
+
+// shortIDFlag is the bit of the TaskID values parsed from a short ID
+const shortIDFlag TaskID = 1 << 63
+
+// shortID returns the short ID of the given counter value
+func shortID(counter uint64) string {
+	return ShortIDPrefix + strconv.FormatUint(counter, 36)
+}
+
+// ParseTaskID returns the TaskID specified by the value, that is either a UID
+// or a short ID (to be resolved by a journal, see TaskJournal.Resolve).
+func ParseTaskID(value string) (TaskID, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if strings.HasPrefix(value, ShortIDPrefix) {
+		counter, err := strconv.ParseUint(strings.TrimPrefix(value, ShortIDPrefix), 36, 63)
+		if err != nil || counter == 0 {
+			return NoUID, fmt.Errorf("ERR: %s is not a valid short ID", value)
+		}
+		return shortIDFlag | TaskID(counter), nil
+	}
+	index, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return NoUID, err
+	}
+	return TaskID(index), nil
+}
+
+// parseUID returns the UID specified by the value, that must be a plain UID
+// (the short IDs are only accepted on the command line, not in the imported
+// files).
+func parseUID(value string) (TaskID, error) {
+	index, err := strconv.ParseUint(value, 10, 63)
+	if err != nil {
+		return NoUID, fmt.Errorf("ERR: %s is not a valid task index", value)
+	}
+	return TaskID(index), nil
+}
+
+// isShortID returns true if this TaskID has been parsed from a short ID
+func (taskID TaskID) isShortID() bool {
+	return taskID&shortIDFlag != 0
+}
+
+// label returns the string that specifies this TaskID on the command line
+func (taskID TaskID) label() string {
+	if taskID.isShortID() {
+		return shortID(uint64(taskID &^ shortIDFlag))
+	}
+	return fmt.Sprintf("%d", taskID)
+}
+
+// nextShortID increments the counter of short IDs of this journal and returns
+// the corresponding short ID (skipping the short IDs already used).
+func (journal *TaskJournal) nextShortID() string {
+	for {
+		journal.ShortIDCounter++
+		sid := shortID(journal.ShortIDCounter)
+		if journal.TaskList.index(func(task Task) bool { return task.ShortID == sid }) == noIndex {
+			return sid
+		}
+	}
+}
+
+// UnassignedShortIDs returns the UIDs of the tasks of this journal that have
+// no short ID (the tasks created by the former versions of todo) or whose
+// short ID is already used by a previous task.
+func (journal TaskJournal) UnassignedShortIDs() TaskIDArray {
+	used := make(map[string]bool)
+	uindeces := make(TaskIDArray, 0)
+	for _, task := range journal.TaskList {
+		if task.ShortID == "" || used[task.ShortID] {
+			uindeces = append(uindeces, task.UIndex)
+		}
+		used[task.ShortID] = true
+	}
+	return uindeces
+}
+
+// AssignShortIDs gives a new short ID to the tasks of this journal returned by
+// UnassignedShortIDs. Returns the number of assigned short IDs.
+func (journal *TaskJournal) AssignShortIDs() int {
+	uindeces := journal.UnassignedShortIDs()
+	for _, uindex := range uindeces {
+		task, _ := journal.GetTask(uindex)
+		task.ShortID = journal.nextShortID()
+	}
+	return len(uindeces)
+}
+
+// Resolve returns the UID of the task specified by uindex, that is either a
+// UID (returned unchanged) or a short ID parsed by ParseTaskID. Returns an
+// error if no task of this journal has the short ID.
+func (journal TaskJournal) Resolve(uindex TaskID) (TaskID, error) {
+	if !uindex.isShortID() {
+		return uindex, nil
+	}
+	sid := uindex.label()
+	index := journal.TaskList.index(func(task Task) bool { return task.ShortID == sid })
+	if index == noIndex {
+		return NoUID, fmt.Errorf("ERR: The task %s does not exist", sid)
+	}
+	return journal.TaskList[index].UIndex, nil
+}
+
+// ResolveAll returns the UIDs of the tasks specified by the uindeces (see
+// Resolve).
+func (journal TaskJournal) ResolveAll(uindeces TaskIDArray) (TaskIDArray, error) {
+	uids := make(TaskIDArray, len(uindeces))
+	for i, uindex := range uindeces {
+		uid, err := journal.Resolve(uindex)
+		if err != nil {
+			return nil, err
+		}
+		uids[i] = uid
+	}
+	return uids, nil
+}
@@ -0,0 +1,90 @@
+package todo
+
+import (
+	"testing"
+)
+
+func TestParseTaskID(t *testing.T) {
+	uindex, err := ParseTaskID("12")
+	if err != nil || uindex != 12 {
+		t.Errorf("The TaskID is %d (should be %d): %v", uindex, 12, err)
+	}
+
+	uindex, err = ParseTaskID("T1a")
+	if err != nil || !uindex.isShortID() {
+		t.Errorf("The TaskID %d should be a short ID: %v", uindex, err)
+	}
+	if uindex.label() != "t1a" {
+		t.Errorf("The label is %s (should be %s)", uindex.label(), "t1a")
+	}
+
+	for _, value := range []string{"t", "t0", "t1-", "x12", "-1"} {
+		if _, err = ParseTaskID(value); err == nil {
+			t.Errorf("The value %s should not be a valid TaskID", value)
+		}
+	}
+
+	var uindeces TaskIDArray
+	err = uindeces.Set("3,t2,t10")
+	if err != nil {
+		t.Error(err)
+	}
+	if uindeces.String() != "[3 t2 t10]" {
+		t.Errorf("The TaskIDs are %s (should be %s)", uindeces.String(), "[3 t2 t10]")
+	}
+}
+
+func TestTaskJournalShortIDs(t *testing.T) {
+	var journal TaskJournal
+	for i := 0; i < 3; i++ {
+		journal.New("task")
+	}
+	task, _ := journal.GetTask(3)
+	if task.ShortID != "t3" {
+		t.Errorf("The short ID is %s (should be %s)", task.ShortID, "t3")
+	}
+
+	// The UID of a deleted task is recycled, but not its short ID
+	journal.Delete(3)
+	task = journal.New("new task")
+	if task.UIndex != 3 || task.ShortID != "t4" {
+		t.Errorf("The IDs are %d and %s (should be %d and %s)", task.UIndex, task.ShortID, 3, "t4")
+	}
+
+	sid, _ := ParseTaskID("t4")
+	uindeces, err := journal.ResolveAll(TaskIDArray{sid, 1})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(uindeces) != 2 || uindeces[0] != 3 || uindeces[1] != 1 {
+		t.Errorf("The resolved UIDs are %v (should be %v)", uindeces, TaskIDArray{3, 1})
+	}
+	sid, _ = ParseTaskID("t3")
+	if _, err = journal.Resolve(sid); err == nil {
+		t.Error("The task t3 does not exist and should raise an error")
+	}
+}
+
+func TestTaskJournalAssignShortIDs(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	journal.TaskList[1].ShortID = "t2"
+	journal.TaskList[2].ShortID = "t2"
+
+	if unassigned := journal.UnassignedShortIDs(); len(unassigned) != len(journal.TaskList)-1 || unassigned[0] != journal.TaskList[0].UIndex {
+		t.Errorf("The tasks with no unique short ID are %v", unassigned)
+	}
+	nassigned := journal.AssignShortIDs()
+	if nassigned != len(journal.TaskList)-1 {
+		t.Errorf("Nb assigned short IDs is %d (should be %d)", nassigned, len(journal.TaskList)-1)
+	}
+	used := make(map[string]bool)
+	for _, task := range journal.TaskList {
+		if task.ShortID == "" || used[task.ShortID] {
+			t.Errorf("The short ID %q of the task %d is not unique", task.ShortID, task.UIndex)
+		}
+		used[task.ShortID] = true
+	}
+	if journal.AssignShortIDs() != 0 {
+		t.Error("All the tasks should already have a short ID")
+	}
+}
@@ -0,0 +1,395 @@
+package todo
+
+// Implementation of the statistics on the tasks of one or several contexts.
+// The statistics are computed from the tasks of the journals and of the
+// archives, since the archived tasks are mostly the work that has been done.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	statsDay         = 24 * 60 * 60 // (in seconds)
+	statsWeek        = 7 * statsDay
+	statsBarWidth    = 40 // maximal length of the bars of the histograms
+	statsChartWidth  = 60 // maximal number of columns of the burnup chart
+	statsChartHeight = 10 // number of rows of the burnup chart
+	statsSeparator   = "\n------------------------------------------------------\n"
+)
+
+// statsAgeBuckets are the classes of the age distribution of the open tasks
+var statsAgeBuckets = []struct {
+	label string
+	max   int64
+}{
+	{"< 1 week", statsWeek},
+	{"1-4 weeks", 4 * statsWeek},
+	{"1-3 months", 13 * statsWeek},
+	{"3-12 months", 52 * statsWeek},
+	{"> 1 year", math.MaxInt64},
+}
+
+// statsRecord is a task of the statistics, with its context and its project
+type statsRecord struct {
+	context  string
+	task     Task
+	project  Task // root ancestor of the task (UIndex is NoUID if none)
+	archived bool
+}
+
+// doneDate returns the date when the task has been done, and 0 if the task is
+// not done. If the completion date is unknown, the creation date is used.
+func (record statsRecord) doneDate() int64 {
+	if record.task.Status != StatusDone {
+		return 0
+	}
+	if completion := record.task.CompletionDate(); completion != 0 {
+		return completion
+	}
+	return record.task.Timestamp
+}
+
+// statsCount counts the tasks of the journal by status, and the tasks of the
+// archive (whatever their status) in the last element.
+type statsCount [4]int
+
+const statsArchived = 3
+
+func (count *statsCount) add(record statsRecord) {
+	if record.archived {
+		count[statsArchived]++
+	} else {
+		count[record.task.Status]++
+	}
+}
+
+func (count statsCount) total() int {
+	return count[StatusTodo] + count[StatusDoing] + count[StatusDone] + count[statsArchived]
+}
+
+// TaskStats gathers the tasks of the journals and of the archives of some
+// contexts, to compute statistics on the work in progress and on the work
+// done.
+type TaskStats struct {
+	records  []statsRecord
+	contexts []string
+}
+
+// Add adds the tasks of the journal and of the archive of the given context
+// to these statistics. The project of a task is its root ancestor (a task with
+// no parent and no children has no project). The archived tasks keep the UIDs
+// of their parents, so that their project is found in the journal as long as
+// the parent has not been archived too.
+func (stats *TaskStats) Add(context string, journal TaskJournal, archive TaskJournal) {
+	stats.contexts = append(stats.contexts, context)
+	tree := make(treeMap, 0)
+	tree.initialize(journal.TaskList)
+	project := func(task Task) Task {
+		root := journal.TaskList.rootAncestor(task)
+		if root.UIndex == task.UIndex && len(tree[task.UIndex]) == 0 {
+			return Task{}
+		}
+		return root
+	}
+	for _, task := range journal.TaskList {
+		stats.records = append(stats.records, statsRecord{context: context, task: task, project: project(task)})
+	}
+	for _, task := range archive.TaskList {
+		record := statsRecord{context: context, task: task, archived: true}
+		if task.ParentID != NoUID {
+			record.project = project(task)
+		}
+		stats.records = append(stats.records, record)
+	}
+}
+
+// Report returns the text report of these statistics, computed at the date
+// now. The throughput and the burnup chart cover the given number of weeks
+// before now.
+func (stats TaskStats) Report(now int64, weeks int) string {
+	s := "Status:\n" + stats.statusString()
+	if len(stats.contexts) > 1 {
+		s += statsSeparator + "Contexts:\n" + stats.contextString()
+	}
+	s += statsSeparator + "Projects:\n" + stats.projectString()
+	s += statsSeparator + "Age of the open tasks:\n" + stats.ageString(now)
+	s += statsSeparator + "Throughput (tasks done per week):\n" + stats.throughputString(now, weeks)
+	s += statsSeparator + "Burnup chart:\n" + stats.burnupString(now, weeks)
+	return s
+}
+
+// statsTable returns a table whose rows are the counts of the given names
+func statsTable(names []string, counts map[string]*statsCount) string {
+	width := len("Total")
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	s := fmt.Sprintf("%-*s %8s %8s %8s %8s %8s\n", width, "", StatusTodo.Label(), StatusDoing.Label(), StatusDone.Label(), "archived", "total")
+	var sum statsCount
+	for _, name := range names {
+		count := counts[name]
+		s += fmt.Sprintf("%-*s %8d %8d %8d %8d %8d\n", width, name, count[StatusTodo], count[StatusDoing], count[StatusDone], count[statsArchived], count.total())
+		for i := range sum {
+			sum[i] += count[i]
+		}
+	}
+	if len(names) > 1 {
+		s += fmt.Sprintf("%-*s %8d %8d %8d %8d %8d\n", width, "Total", sum[StatusTodo], sum[StatusDoing], sum[StatusDone], sum[statsArchived], sum.total())
+	}
+	return s
+}
+
+// statsBar returns the bar of a histogram (a non zero value has a visible bar)
+func statsBar(value int, max int) string {
+	if value == 0 || max == 0 {
+		return ""
+	}
+	size := value * statsBarWidth / max
+	if size == 0 {
+		size = 1
+	}
+	return strings.Repeat("#", size)
+}
+
+func (stats TaskStats) statusString() string {
+	var journalCount, archiveCount statsCount
+	for _, record := range stats.records {
+		if record.archived {
+			archiveCount[record.task.Status]++
+		} else {
+			journalCount[record.task.Status]++
+		}
+	}
+	s := fmt.Sprintf("%-8s %8s %8s %8s\n", "", "journal", "archive", "total")
+	for _, status := range []TaskStatus{StatusTodo, StatusDoing, StatusDone} {
+		s += fmt.Sprintf("%-8s %8d %8d %8d\n", status.Label(), journalCount[status], archiveCount[status], journalCount[status]+archiveCount[status])
+	}
+	njournal := journalCount.total()
+	narchive := archiveCount.total()
+	s += fmt.Sprintf("%-8s %8d %8d %8d\n", "Total", njournal, narchive, njournal+narchive)
+	return s
+}
+
+func (stats TaskStats) contextString() string {
+	counts := make(map[string]*statsCount)
+	for _, context := range stats.contexts {
+		counts[context] = new(statsCount)
+	}
+	for _, record := range stats.records {
+		counts[record.context].add(record)
+	}
+	return statsTable(stats.contexts, counts)
+}
+
+func (stats TaskStats) projectString() string {
+	const noProject = "(no project)"
+	names := make([]string, 0)
+	counts := make(map[string]*statsCount)
+	for _, record := range stats.records {
+		name := noProject
+		if record.project.UIndex != NoUID {
+			name = fmt.Sprintf("%d - %s", record.project.UIndex, record.project.Description)
+			if len(stats.contexts) > 1 {
+				name = fmt.Sprintf("%s:%s", record.context, name)
+			}
+		}
+		if counts[name] == nil {
+			counts[name] = new(statsCount)
+			if name != noProject {
+				names = append(names, name)
+			}
+		}
+		counts[name].add(record)
+	}
+	if counts[noProject] != nil {
+		names = append(names, noProject)
+	}
+	if len(names) == 0 {
+		return notasks + "\n"
+	}
+	return statsTable(names, counts)
+}
+
+func (stats TaskStats) ageString(now int64) string {
+	counts := make([]int, len(statsAgeBuckets))
+	ages := make([]int64, 0)
+	for _, record := range stats.records {
+		if record.archived || record.task.Status == StatusDone {
+			continue
+		}
+		age := now - record.task.Timestamp
+		ages = append(ages, age)
+		for i, bucket := range statsAgeBuckets {
+			if age < bucket.max {
+				counts[i]++
+				break
+			}
+		}
+	}
+	if len(ages) == 0 {
+		return notasks + "\n"
+	}
+	max := 0
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+	s := ""
+	for i, bucket := range statsAgeBuckets {
+		s += strings.TrimRight(fmt.Sprintf("%-12s %4d %s", bucket.label, counts[i], statsBar(counts[i], max)), " ") + "\n"
+	}
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+	s += fmt.Sprintf("Median age: %d days, oldest: %d days\n", ages[len(ages)/2]/statsDay, ages[len(ages)-1]/statsDay)
+	return s
+}
+
+// throughput returns the number of tasks done during each of the given
+// number of weeks before now (the last one is the current week).
+func (stats TaskStats) throughput(now int64, weeks int) []int {
+	counts := make([]int, weeks)
+	start := weekStart(now, weeks-1)
+	for _, record := range stats.records {
+		date := record.doneDate()
+		if date == 0 || date < start.Unix() {
+			continue
+		}
+		week := 0
+		for week < weeks-1 && date >= start.AddDate(0, 0, 7*(week+1)).Unix() {
+			week++
+		}
+		counts[week]++
+	}
+	return counts
+}
+
+func (stats TaskStats) throughputString(now int64, weeks int) string {
+	counts := stats.throughput(now, weeks)
+	max, sum := 0, 0
+	for _, count := range counts {
+		sum += count
+		if count > max {
+			max = count
+		}
+	}
+	s := ""
+	for week, count := range counts {
+		label := weekStart(now, weeks-1-week).Format(layoutISO)
+		s += strings.TrimRight(fmt.Sprintf("%s %4d %s", label, count, statsBar(count, max)), " ") + "\n"
+	}
+	s += fmt.Sprintf("Average: %.1f tasks per week\n", float64(sum)/float64(weeks))
+	return s
+}
+
+// burnup returns the dates of the columns of the burnup chart over the given
+// number of weeks before now, and at each date the scope (the number of tasks
+// created) and the number of tasks done.
+func (stats TaskStats) burnup(now int64, weeks int) (dates []int64, scope []int, done []int) {
+	start := weekStart(now, weeks-1).Unix()
+	days := int((now-start)/statsDay) + 1
+	step := (days + statsChartWidth - 1) / statsChartWidth
+	for day := step; ; day += step {
+		date := start + int64(day)*statsDay
+		if date > now {
+			date = now
+		}
+		dates = append(dates, date)
+		if date == now {
+			break
+		}
+	}
+	scope = make([]int, len(dates))
+	done = make([]int, len(dates))
+	for _, record := range stats.records {
+		doneDate := record.doneDate()
+		for i, date := range dates {
+			if record.task.Timestamp <= date {
+				scope[i]++
+			}
+			if doneDate != 0 && doneDate <= date {
+				done[i]++
+			}
+		}
+	}
+	return dates, scope, done
+}
+
+// burnupString returns an ASCII chart of the burnup (the tasks done, drawn
+// with #) and of the burndown (the remaining tasks, drawn with . above the
+// tasks done), one column for each period of the window.
+func (stats TaskStats) burnupString(now int64, weeks int) string {
+	dates, scope, done := stats.burnup(now, weeks)
+	max := 0
+	for _, value := range scope {
+		if value > max {
+			max = value
+		}
+	}
+	if max == 0 {
+		return notasks + "\n"
+	}
+	axisWidth := len(fmt.Sprint(max))
+	s := ""
+	for row := statsChartHeight; row >= 1; row-- {
+		level := float64(max) * (float64(row) - 0.5) / statsChartHeight
+		label := ""
+		if row == statsChartHeight {
+			label = fmt.Sprint(max)
+		}
+		line := fmt.Sprintf("%*s |", axisWidth, label)
+		for i := range dates {
+			switch {
+			case float64(done[i]) >= level:
+				line += "#"
+			case float64(scope[i]) >= level:
+				line += "."
+			default:
+				line += " "
+			}
+		}
+		s += strings.TrimRight(line, " ") + "\n"
+	}
+	s += fmt.Sprintf("%*d +%s\n", axisWidth, 0, strings.Repeat("-", len(dates)))
+	start := weekStart(now, weeks-1)
+	first := start.Format(layoutISO)
+	last := time.Unix(now, 0).Format(layoutISO)
+	padding := len(dates) - len(first) - len(last)
+	if padding < 1 {
+		padding = 1
+	}
+	s += fmt.Sprintf("%*s  %s%s%s\n", axisWidth, "", first, strings.Repeat(" ", padding), last)
+	s += "Legend: # done  . remaining\n"
+	n := len(dates) - 1
+	s += fmt.Sprintf("Scope: %d tasks (%+d in the window), done: %d (%+d in the window), remaining: %d\n",
+		scope[n], scope[n]-stats.countCreatedBefore(start.Unix()),
+		done[n], done[n]-stats.countDoneBefore(start.Unix()), scope[n]-done[n])
+	return s
+}
+
+// countCreatedBefore returns the number of tasks created before the date
+func (stats TaskStats) countCreatedBefore(date int64) int {
+	count := 0
+	for _, record := range stats.records {
+		if record.task.Timestamp < date {
+			count++
+		}
+	}
+	return count
+}
+
+// countDoneBefore returns the number of tasks done before the date
+func (stats TaskStats) countDoneBefore(date int64) int {
+	count := 0
+	for _, record := range stats.records {
+		if doneDate := record.doneDate(); doneDate != 0 && doneDate < date {
+			count++
+		}
+	}
+	return count
+}
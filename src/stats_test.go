@@ -0,0 +1,94 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// createStatsJournals creates a journal and an archive whose tasks have been
+// created and done during the weeks before the given date.
+func createStatsJournals(now int64) (TaskJournal, TaskJournal) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	for i := range journal.TaskList {
+		journal.TaskList[i].Timestamp = now - int64(i)*statsDay
+		journal.TaskList[i].History = nil
+	}
+	task, _ := journal.GetTask(13)
+	task.Status = StatusDone
+	task.History = StatusHistory{{Status: StatusDone, Timestamp: now - statsDay}}
+
+	archive := TaskJournal{TaskList: TaskArray{
+		{UIndex: 1001, Timestamp: now - 30*statsDay, Status: StatusDone, ParentID: 12,
+			History: StatusHistory{{Status: StatusDone, Timestamp: now - 8*statsDay}}},
+		{UIndex: 1002, Timestamp: now - 400*statsDay, Status: StatusDone},
+	}}
+	return journal, archive
+}
+
+func TestStatsReport(t *testing.T) {
+	now := time.Date(2024, 3, 14, 12, 0, 0, 0, time.Local).Unix()
+	journal, archive := createStatsJournals(now)
+	var stats TaskStats
+	stats.Add("work", journal, archive)
+
+	report := stats.Report(now, 4)
+	printlog(report)
+
+	for _, section := range []string{"Status:", "Projects:", "Age of the open tasks:", "Throughput", "Burnup chart:"} {
+		if !strings.Contains(report, section) {
+			t.Errorf("The section %s is missing", section)
+		}
+	}
+	if strings.Contains(report, "Contexts:") {
+		t.Error("The section Contexts should be printed only for several contexts")
+	}
+
+	// The archived task 1001 belongs to the project of its parent in the journal
+	parent, _ := journal.GetTask(12)
+	project := journal.TaskList.rootAncestor(*parent)
+	for _, record := range stats.records {
+		if record.task.UIndex == 1001 && record.project.UIndex != project.UIndex {
+			t.Errorf("The project is %d (should be %d)", record.project.UIndex, project.UIndex)
+		}
+		if record.task.UIndex == 1002 && record.project.UIndex != NoUID {
+			t.Errorf("The task 1002 should have no project")
+		}
+	}
+}
+
+func TestStatsThroughput(t *testing.T) {
+	now := time.Date(2024, 3, 14, 12, 0, 0, 0, time.Local).Unix() // a thursday
+	journal, archive := createStatsJournals(now)
+	var stats TaskStats
+	stats.Add("work", journal, archive)
+
+	// The task 13 is done this week, the task 1001 the week before and the
+	// task 1002 (no completion date, created 400 days ago) is out of the window
+	counts := stats.throughput(now, 4)
+	expected := []int{0, 0, 1, 1}
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("The throughput is %v (should be %v)", counts, expected)
+			break
+		}
+	}
+
+	dates, scope, done := stats.burnup(now, 4)
+	n := len(dates) - 1
+	if dates[n] != now {
+		t.Errorf("The last date is %d (should be %d)", dates[n], now)
+	}
+	if scope[n] != len(journal.TaskList)+len(archive.TaskList) {
+		t.Errorf("The scope is %d (should be %d)", scope[n], len(journal.TaskList)+len(archive.TaskList))
+	}
+	if done[n] != 3 {
+		t.Errorf("Nb done is %d (should be %d)", done[n], 3)
+	}
+	for i := 1; i < len(dates); i++ {
+		if scope[i] < scope[i-1] || done[i] < done[i-1] || done[i] > scope[i] {
+			t.Errorf("The burnup is not consistent at %d: scope %v done %v", i, scope, done)
+			break
+		}
+	}
+}
@@ -116,3 +116,8 @@ func (status TaskStatus) legend() string {
 	legend := fmt.Sprintf("%s %s", renderingMap[status], status.Label())
 	return statusRenderingFunction(legend, status)
 }
+
+// legendString returns the legend of the status symbols used in the listings
+func legendString() string {
+	return fmt.Sprintf("Legend: %s  %s  %s", StatusTodo.legend(), StatusDoing.legend(), StatusDone.legend())
+}
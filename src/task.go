@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"text/template"
+	"time"
 )
 
 // =========================================================================
@@ -35,34 +35,38 @@ const (
 // a list of task indeces.
 //
 func (taskID *TaskID) String() string {
-	return fmt.Sprintf("%d", *taskID)
+	return taskID.label()
 }
 
-// Set implement the flag.Value interface
+// Set implement the flag.Value interface (the value is a UID or a short ID)
 func (taskID *TaskID) Set(value string) error {
-	index, err := strconv.ParseUint(value, 10, 64)
+	index, err := ParseTaskID(value)
 	if err != nil {
 		return err
 	}
-	(*taskID) = TaskID(index)
+	(*taskID) = index
 	return nil
 }
 
 // String implement the flag.Value interface
 func (il *TaskIDArray) String() string {
-	return fmt.Sprintf("%v", *il)
+	labels := make([]string, len(*il))
+	for i, taskID := range *il {
+		labels[i] = taskID.label()
+	}
+	return fmt.Sprintf("[%s]", strings.Join(labels, " "))
 }
 
-// Set implement the flag.Value interface
+// Set implement the flag.Value interface (the values are UIDs or short IDs)
 func (il *TaskIDArray) Set(value string) error {
 	sl := strings.Split(value, ",")
 	*il = make(TaskIDArray, len(sl))
 	for i := 0; i < len(sl); i++ {
-		index, err := strconv.ParseUint(sl[i], 10, 64)
+		index, err := ParseTaskID(sl[i])
 		if err != nil {
 			return err
 		}
-		(*il)[i] = TaskID(index)
+		(*il)[i] = index
 	}
 	return nil
 }
@@ -72,25 +76,93 @@ func (il *TaskIDArray) Set(value string) error {
 
 // Task is the data structure for a single task
 type Task struct {
-	UIndex      TaskID     // Usage Index (could be recycled)
-	GIndex      TaskID     // Global Index (invariant and unique)
-	Timestamp   int64      // Date of the task (unix format)
-	Description string     // Description of the Task
-	Status      TaskStatus // Status of the task
-	OnBoard     bool       // True if the task is on board
-	NotePath    string     // Path to the note file (relative to the db root)
-	ParentID    TaskID     // UID of the parent task
-}
-
-// initGlobalIndex initialises the global index of this task.
-// We create a global index (unique and invariant ever) by creating a hash integer
-// from a string representation of the task and its timestamp. The string
-// representation is a composition of the usage id, the timestamp and the
-// description. The objective is to make it impossible ever to have two
-// tasks with the same global index.
+	UIndex      TaskID        // Usage Index (could be recycled)
+	GIndex      TaskID        // Global Index (compact alias of the task, unique in its context only)
+	GUID        string        `json:",omitempty"` // Global identifier (the stable identity of the task), a ULID
+	ShortID     string        `json:",omitempty"` // Short ID (stable and unique in the context), e.g. t1a
+	Timestamp   int64         // Date of the task (unix format)
+	Description string        // Description of the Task
+	Status      TaskStatus    // Status of the task
+	OnBoard     bool          // True if the task is on board
+	NotePath    string        // Path to the note file (relative to the db root)
+	ParentID    TaskID        // UID of the parent task
+	ParentGUID  string        `json:",omitempty"` // GUID of the parent task while it is in another journal (see TaskJournal.Move)
+	Priority    string        `json:",omitempty"` // Priority of the task (A to Z, A is the highest)
+	Due         int64         `json:",omitempty"` // Due date of the task (unix format), 0 if none
+	History     StatusHistory `json:",omitempty"` // History of the status changes
+	progress    Progress      // Progress of the descendants (computed for the rendering, not saved)
+}
+
+// StatusChange records a change of the status of a task
+type StatusChange struct {
+	Status    TaskStatus // Status of the task after the change
+	Timestamp int64      // Date of the change (unix format)
+}
+
+// StatusHistory is the list of the status changes of a task (chronological order)
+type StatusHistory []StatusChange
+
+// SetStatus changes the status of this task and records the change in the
+// history of the task.
+func (task *Task) SetStatus(status TaskStatus) {
+	task.Status = status
+	task.History = append(task.History, StatusChange{Status: status, Timestamp: timestamp()})
+}
+
+// NextStatus makes the status of this task change to its next state
+func (task *Task) NextStatus() error {
+	status := task.Status
+	err := status.Next()
+	if err != nil {
+		return err
+	}
+	task.SetStatus(status)
+	return nil
+}
+
+// PreviousStatus makes the status of this task change to its previous state
+func (task *Task) PreviousStatus() error {
+	status := task.Status
+	err := status.Previous()
+	if err != nil {
+		return err
+	}
+	task.SetStatus(status)
+	return nil
+}
+
+// StatusDate returns the date (unix format) when the task has changed to the
+// given status for the last time, and 0 if it is unknown (the task never had
+// this status or the change was not recorded).
+func (task Task) StatusDate(status TaskStatus) int64 {
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].Status == status {
+			return task.History[i].Timestamp
+		}
+	}
+	return 0
+}
+
+// CompletionDate returns the date (unix format) when the task has been done,
+// and 0 if the task is not done or if the date is unknown.
+func (task Task) CompletionDate() int64 {
+	if task.Status != StatusDone {
+		return 0
+	}
+	return task.StatusDate(StatusDone)
+}
+
+// initGlobalIndex initialises the global identifiers of this task.
+// The GUID (if not defined) is a new ULID created at the date of the task (see
+// newGUID), which makes it impossible to have two tasks with the same GUID.
+// The global index is a compact alias of the GUID, created by composing the
+// date of the task with a hash integer of the GUID (it is made unique in a
+// context by TaskJournal.initGlobalIndex).
 func (task *Task) initGlobalIndex() {
-	taskstr := fmt.Sprintf("%d [%d]: %s", task.UIndex, task.Timestamp, task.Description)
-	task.GIndex = TaskID(hashdate(taskstr, task.Timestamp))
+	if task.GUID == "" {
+		task.GUID = newGUID(time.Unix(task.Timestamp, 0))
+	}
+	task.GIndex = TaskID(hashdate(task.GUID, task.Timestamp))
 }
 
 // String returns a string representation of this task
@@ -101,11 +173,30 @@ func (task Task) String() string {
 // OnelineString returns a string representation of this task on one signe line.
 // This shouldbe used for a pretty presentation of task lists.
 func (task Task) OnelineString() string {
-	t := "%2d %s %s : %s"
-	s := fmt.Sprintf(t, task.UIndex, task.getTaskIndicators(), task.Status.String(), task.Description)
+	if task.ShortID != "" {
+		return task.onelineString(fmt.Sprintf("%2d %-4s", task.UIndex, task.ShortID))
+	}
+	return task.onelineString(fmt.Sprintf("%2d", task.UIndex))
+}
+
+// onelineString returns the one line string representation of this task where
+// the task is identified by the given label (the UID for a single journal).
+func (task Task) onelineString(label string) string {
+	t := "%s %s %s : %s"
+	s := fmt.Sprintf(t, label, task.getTaskIndicators(), task.Status.String(), task.Description)
 	return s
 }
 
+// taskIndicators defines the data that can be used in the indicators template
+// (see the configuration parameter Indicators)
+type taskIndicators struct {
+	Date     string
+	Note     string
+	Board    string
+	Progress string // Number of descendants done, e.g. [3/5] (empty if no children)
+	Percent  string // Percentage of descendants done, e.g. 60% (empty if no children)
+}
+
 func (task Task) getTaskIndicators() string {
 	cfg, _ := GetConfig() // unused to test the err, we can not arrive here in case of config error
 	indicatorsTemplate := cfg.Parameters.Indicators
@@ -130,14 +221,14 @@ func (task Task) getTaskIndicators() string {
 		onBoard = "-"
 	}
 
-	indicators := struct {
-		Date  string
-		Note  string
-		Board string
-	}{
-		Date:  dtlabel,
-		Note:  hasNote,
-		Board: onBoard,
+	indicators := taskIndicators{
+		Date:     dtlabel,
+		Note:     hasNote,
+		Board:    onBoard,
+		Progress: task.progress.String(),
+	}
+	if task.progress.Total > 0 {
+		indicators.Percent = fmt.Sprintf("%d%%", task.progress.Percent())
 	}
 	var buffer bytes.Buffer
 	err = tmpl.Execute(&buffer, indicators)
@@ -163,10 +254,12 @@ func (task Task) InfoString() string {
 
 // CreateTestTask creates a dummy task for test purposes
 func CreateTestTask(uindex TaskID, text string) Task {
+	now := time.Now()
 	var task = Task{
 		UIndex:      uindex,
+		GUID:        newGUID(now),
 		Description: text,
-		Timestamp:   timestamp(),
+		Timestamp:   now.Unix(),
 		Status:      StatusTodo,
 		OnBoard:     false,
 	}
@@ -301,17 +394,26 @@ func (tasks TaskArray) getFreeUID() TaskID {
 	return freeUID
 }
 
-// ancestor returns true if parentId is an ancestor of childID
+// ancestor returns true if parentId is an ancestor of childID. The parent
+// relations are walked up to a root task, a task that does not exist, or a
+// task already visited (a cycle of parent relations).
 func (tasks TaskArray) ancestor(childID TaskID, parentID TaskID) bool {
 	if childID == parentID {
 		return false
 	}
-	task, _ := tasks.getTask(childID)
-	if task.ParentID == NoUID {
-		return false
-	}
-	if task.ParentID == parentID {
-		return true
+	visited := map[TaskID]bool{childID: true}
+	for {
+		task, err := tasks.getTask(childID)
+		if err != nil || task.ParentID == NoUID {
+			return false
+		}
+		if task.ParentID == parentID {
+			return true
+		}
+		if visited[task.ParentID] {
+			return false
+		}
+		visited[task.ParentID] = true
+		childID = task.ParentID
 	}
-	return tasks.ancestor(task.ParentID, parentID)
 }
@@ -2,6 +2,7 @@ package todo
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 )
 
@@ -203,3 +204,73 @@ func TestTaskJournalIO(t *testing.T) {
 	}
 
 }
+
+func TestTaskJournalTransfer(t *testing.T) {
+	rootdir := t.TempDir()
+
+	journal := CreateTestJournal()
+	journal.SaveTo(filepath.Join(rootdir, "src", JournalFilename))
+	ptask, _ := journal.GetTask(3)
+	ptask.ParentID = 2
+	ptask, _ = journal.GetTask(4)
+	ptask.ParentID = 3
+	notepath, err := journal.GetOrCreateNoteFile(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var target TaskJournal
+	target.LoadOrCreate(filepath.Join(rootdir, "dst", JournalFilename))
+	target.New("A task of the target journal")
+
+	uidmap, notepaths, err := journal.Transfer(&target, TaskIDArray{2}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uidmap) != 3 {
+		t.Errorf("Nb transfered tasks is %d (should be %d)", len(uidmap), 3)
+	}
+	if len(target.TaskList) != 4 {
+		t.Errorf("Nb tasks in the target is %d (should be %d)", len(target.TaskList), 4)
+	}
+	// The transfered tasks are removed from the source
+	if len(journal.TaskList) != 1 {
+		t.Errorf("Nb tasks left in the source is %d (should be %d)", len(journal.TaskList), 1)
+	}
+	for uindex := range uidmap {
+		if _, err := journal.GetTask(uindex); err == nil {
+			t.Errorf("The task %d should have been removed from the source", uindex)
+		}
+	}
+	child, _ := target.GetTask(uidmap[4])
+	if child.ParentID != uidmap[3] {
+		t.Errorf("ParentID is %d (should be %d)", child.ParentID, uidmap[3])
+	}
+	root, _ := target.GetTask(uidmap[2])
+	if root.ParentID != NoUID {
+		t.Errorf("ParentID is %d (should be %d)", root.ParentID, NoUID)
+	}
+	// The source note file is left to be removed by the caller
+	if len(notepaths) != 1 || notepaths[0] != notepath {
+		t.Errorf("The note files to remove are %v (should be %v)", notepaths, []string{notepath})
+	}
+	if exists, _ := PathExists(notepath); !exists {
+		t.Errorf("The note file %s should not have been removed", notepath)
+	}
+	newnotepath, _ := target.GetNoteFile(uidmap[3])
+	if exists, _ := PathExists(newnotepath); !exists {
+		t.Errorf("The note file %s should have been moved to the target", newnotepath)
+	}
+
+	// The copied tasks are kept in the source
+	_, _, err = target.Transfer(&journal, TaskIDArray{uidmap[3]}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(target.TaskList) != 4 {
+		t.Errorf("Nb tasks left in the source is %d (should be %d)", len(target.TaskList), 4)
+	}
+	if len(journal.TaskList) != 3 {
+		t.Errorf("Nb tasks in the target is %d (should be %d)", len(journal.TaskList), 3)
+	}
+}
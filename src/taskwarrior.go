@@ -0,0 +1,395 @@
+package todo
+
+// Implementation of the Taskwarrior JSON format (as produced by the command
+// "task export" and read by the command "task import").
+//
+// The attributes of a Taskwarrior task are mapped as follows:
+//
+//   uuid         the GUID written as a uuid (see twUUID)
+//   description  Description
+//   status       pending (todo or doing), completed (done)
+//   start        date of the doing status (a started task is a doing task)
+//   entry, end   Timestamp, completion date
+//   due          Due
+//   priority     H, M, L for the priorities A, B, C (and lower)
+//   project      description of the root ancestor of the task
+//   depends      a parent task depends on its children
+//   tags         the tag "board" for the tasks on board
+//   annotations  content of the note
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	twLayout         = "20060102T150405Z"
+	twStatusPending  = "pending"
+	twStatusComplete = "completed"
+	twStatusDeleted  = "deleted"
+	twStatusRecur    = "recurring"
+	twTagBoard       = "board"
+	twProjectSep     = "."
+	// twUUIDPrefix is the first group of the uuids derived from a GIndex (by
+	// the former versions of todo)
+	twUUIDPrefix = "746f646f"
+)
+
+var twPriorities = []string{"H", "M", "L"}
+
+// twAnnotation is an annotation of a Taskwarrior task
+type twAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// twDepends is the list of the uuids of the tasks a Taskwarrior task depends
+// on. It is a JSON array since Taskwarrior 2.6, and a comma separated string
+// before.
+type twDepends []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (depends *twDepends) UnmarshalJSON(data []byte) error {
+	var uuids []string
+	if err := json.Unmarshal(data, &uuids); err == nil {
+		*depends = uuids
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*depends = twDepends{}
+	for _, uuid := range strings.Split(text, ",") {
+		if uuid = strings.TrimSpace(uuid); uuid != "" {
+			*depends = append(*depends, uuid)
+		}
+	}
+	return nil
+}
+
+// twTask is the JSON representation of a Taskwarrior task
+type twTask struct {
+	ID          TaskID         `json:"id,omitempty"`
+	UUID        string         `json:"uuid"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Entry       string         `json:"entry"`
+	Start       string         `json:"start,omitempty"`
+	End         string         `json:"end,omitempty"`
+	Due         string         `json:"due,omitempty"`
+	Priority    string         `json:"priority,omitempty"`
+	Project     string         `json:"project,omitempty"`
+	Depends     twDepends      `json:"depends,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Annotations []twAnnotation `json:"annotations,omitempty"`
+}
+
+// twUUID returns the Taskwarrior uuid of the task: the 128 bits of its GUID
+// (a ULID has the size of a uuid), read back by twSetGlobalID. A task with no
+// GUID (created by a former version of todo) has a uuid derived from its
+// GIndex (see twGIndexUUID).
+func twUUID(task Task) string {
+	bits, ok := decodeGUID(task.GUID)
+	if !ok {
+		return twGIndexUUID(task.GIndex)
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bits[0:4], bits[4:6], bits[6:8], bits[8:10], bits[10:16])
+}
+
+// twSetGlobalID sets the GUID of the task from its Taskwarrior uuid. A uuid
+// derived from a GIndex is ignored (the GIndex is not an identity, see
+// setGlobalID).
+func twSetGlobalID(task *Task, uuid string) {
+	if twGIndex(uuid) != NoUID {
+		return
+	}
+	hex := strings.ReplaceAll(uuid, "-", "")
+	if len(hex) != 32 {
+		return
+	}
+	var bits [16]byte
+	for i := 0; i < len(bits); i++ {
+		value, err := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return
+		}
+		bits[i] = byte(value)
+	}
+	task.GUID = encodeGUID(bits)
+}
+
+// twGIndexUUID returns the Taskwarrior uuid corresponding to the given GIndex.
+// The 64 bits of the GIndex are written in the last four groups of a version 4
+// uuid, so that the GIndex can be read back by twGIndex.
+func twGIndexUUID(gindex TaskID) string {
+	return fmt.Sprintf("%s-%04x-4%03x-8%03x-%012x", twUUIDPrefix,
+		uint64(gindex)>>48, (uint64(gindex)>>36)&0xfff, (uint64(gindex)>>24)&0xfff, uint64(gindex)&0xffffff)
+}
+
+// twGIndex returns the GIndex encoded in the uuid by twGIndexUUID, or NoUID if
+// the uuid has not been created by twGIndexUUID.
+func twGIndex(uuid string) TaskID {
+	groups := strings.Split(uuid, "-")
+	if len(groups) != 5 || groups[0] != twUUIDPrefix || len(groups[2]) != 4 || len(groups[3]) != 4 {
+		return NoUID
+	}
+	// The groups are the 16, 12, 12 and 24 bits of the GIndex (high to low)
+	hexs := []string{groups[1], groups[2][1:], groups[3][1:], groups[4]}
+	widths := []uint{16, 12, 12, 24}
+	var gindex uint64
+	for i, hex := range hexs {
+		bits, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil || bits >= 1<<widths[i] {
+			return NoUID
+		}
+		gindex = gindex<<widths[i] | bits
+	}
+	return TaskID(gindex)
+}
+
+// twDate returns the Taskwarrior representation of a date (blank if undefined)
+func twDate(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).UTC().Format(twLayout)
+}
+
+// parseTwDate parses a Taskwarrior date (0 if blank)
+func parseTwDate(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	date, err := time.Parse(twLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("ERR: the date %s is not a valid Taskwarrior date", value)
+	}
+	return date.Unix(), nil
+}
+
+// TaskwarriorJSON returns the Taskwarrior JSON representation of the tasks of
+// this journal that satisfy the given filter.
+func (journal TaskJournal) TaskwarriorJSON(taskFilter TaskFilter) (string, error) {
+	twTasks := make([]twTask, 0)
+	for _, task := range journal.TaskList {
+		if !taskFilter(task) {
+			continue
+		}
+		twtask := twTask{
+			ID:          task.UIndex,
+			UUID:        twUUID(task),
+			Description: task.Description,
+			Status:      twStatusPending,
+			Entry:       twDate(task.Timestamp),
+			Due:         twDate(task.Due),
+		}
+		switch task.Status {
+		case StatusDoing:
+			start := task.StatusDate(StatusDoing)
+			if start == 0 {
+				start = task.Timestamp
+			}
+			twtask.Start = twDate(start)
+		case StatusDone:
+			end := task.CompletionDate()
+			if end == 0 {
+				end = task.Timestamp
+			}
+			twtask.ID = NoUID
+			twtask.Status = twStatusComplete
+			twtask.End = twDate(end)
+		}
+		if len(task.Priority) == 1 {
+			idx := int(task.Priority[0]) - 'A'
+			if idx >= len(twPriorities) {
+				idx = len(twPriorities) - 1
+			}
+			if idx >= 0 {
+				twtask.Priority = twPriorities[idx]
+			}
+		}
+		if root := journal.TaskList.rootAncestor(task); root.UIndex != task.UIndex {
+			twtask.Project = root.Description
+		}
+		for _, child := range journal.TaskList {
+			if child.ParentID == task.UIndex && child.UIndex != task.UIndex {
+				twtask.Depends = append(twtask.Depends, twUUID(child))
+			}
+		}
+		if task.OnBoard {
+			twtask.Tags = []string{twTagBoard}
+		}
+		if body, err := journal.GetNoteContent(task.UIndex); err == nil && body != "" {
+			annotation := twAnnotation{Entry: twDate(task.Timestamp), Description: body}
+			twtask.Annotations = []twAnnotation{annotation}
+		}
+		twTasks = append(twTasks, twtask)
+	}
+	bytes, err := json.MarshalIndent(twTasks, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes) + "\n", nil
+}
+
+// parseTwTasks reads the Taskwarrior tasks of the text, which is either a JSON
+// array (Taskwarrior 2.6 and later) or a list of JSON objects, one per line.
+func parseTwTasks(text string) ([]twTask, error) {
+	var twTasks []twTask
+	if strings.HasPrefix(strings.TrimSpace(text), "[") {
+		err := json.Unmarshal([]byte(text), &twTasks)
+		return twTasks, err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	nline := 0
+	for scanner.Scan() {
+		nline++
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ",")
+		if line == "" {
+			continue
+		}
+		var twtask twTask
+		if err := json.Unmarshal([]byte(line), &twtask); err != nil {
+			return nil, fmt.Errorf("ERR: line %d: %s", nline, err)
+		}
+		twTasks = append(twTasks, twtask)
+	}
+	return twTasks, scanner.Err()
+}
+
+// ParseTaskwarriorJSON parses the Taskwarrior JSON text and returns the list
+// of tasks and the contents of their notes (the annotations). The UIndex of
+// the tasks are their order numbers in the text. A task becomes the parent of
+// the tasks it depends on (unless they already have a parent or it would
+// create a cycle). A task with a project and no parent becomes the child of
+// the task whose description is the project name (a dotted project name is a
+// hierarchy of projects, and the project tasks are created if they do not
+// exist). The deleted tasks and the templates of the recurring tasks are
+// ignored, as well as the tags other than "board".
+func ParseTaskwarriorJSON(text string) (TaskArray, map[TaskID]string, error) {
+	twTasks, err := parseTwTasks(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	imported := make([]twTask, 0, len(twTasks))
+	for _, twtask := range twTasks {
+		if twtask.Status != twStatusDeleted && twtask.Status != twStatusRecur {
+			imported = append(imported, twtask)
+		}
+	}
+
+	tasks := make(TaskArray, 0, len(imported))
+	notes := make(map[TaskID]string)
+	uids := make(map[string]TaskID) // uuid => UIndex
+	for _, twtask := range imported {
+		task := Task{
+			UIndex:      TaskID(len(tasks) + 1),
+			Timestamp:   timestamp(),
+			Description: twtask.Description,
+			Status:      StatusTodo,
+		}
+		twSetGlobalID(&task, twtask.UUID)
+		if twtask.Entry != "" {
+			if task.Timestamp, err = parseTwDate(twtask.Entry); err != nil {
+				return nil, nil, err
+			}
+		}
+		if task.Due, err = parseTwDate(twtask.Due); err != nil {
+			return nil, nil, err
+		}
+		start, err := parseTwDate(twtask.Start)
+		if err != nil {
+			return nil, nil, err
+		}
+		end, err := parseTwDate(twtask.End)
+		if err != nil {
+			return nil, nil, err
+		}
+		if start != 0 {
+			task.Status = StatusDoing
+			task.History = append(task.History, StatusChange{Status: StatusDoing, Timestamp: start})
+		}
+		if twtask.Status == twStatusComplete {
+			task.Status = StatusDone
+			if end != 0 {
+				task.History = append(task.History, StatusChange{Status: StatusDone, Timestamp: end})
+			}
+		}
+		for i, priority := range twPriorities {
+			if strings.ToUpper(twtask.Priority) == priority {
+				task.Priority = string(rune('A' + i))
+			}
+		}
+		for _, tag := range twtask.Tags {
+			task.OnBoard = task.OnBoard || tag == twTagBoard
+		}
+		annotations := make([]string, 0, len(twtask.Annotations))
+		for _, annotation := range twtask.Annotations {
+			annotations = append(annotations, annotation.Description)
+		}
+		if len(annotations) > 0 {
+			notes[task.UIndex] = strings.Join(annotations, "\n\n")
+		}
+		if twtask.UUID != "" {
+			uids[twtask.UUID] = task.UIndex
+		}
+		tasks = append(tasks, task)
+	}
+
+	// The dependencies are resolved into parent relations (the tasks and the
+	// imported Taskwarrior tasks have the same indeces)
+	for i, twtask := range imported {
+		for _, uuid := range twtask.Depends {
+			uid, exists := uids[uuid]
+			if !exists {
+				continue
+			}
+			child, _ := tasks.getTask(uid)
+			if child.ParentID == NoUID && uid != tasks[i].UIndex && !tasks.ancestor(tasks[i].UIndex, uid) {
+				child.ParentID = tasks[i].UIndex
+			}
+		}
+	}
+
+	// The projects are resolved into parent relations
+	for i, twtask := range imported {
+		if twtask.Project == "" || tasks[i].ParentID != NoUID {
+			continue
+		}
+		parentID := NoUID
+		for _, name := range strings.Split(twtask.Project, twProjectSep) {
+			parentID = tasks.projectTask(name, parentID, tasks[i].Timestamp)
+		}
+		if parentID != tasks[i].UIndex && !tasks.ancestor(parentID, tasks[i].UIndex) {
+			tasks[i].ParentID = parentID
+		}
+	}
+	return tasks, notes, nil
+}
+
+// projectTask returns the UID of the task that represents the project name as
+// a child of the task parentID (a root task if parentID is NoUID). The project
+// task is created if it does not exist.
+func (tasks *TaskArray) projectTask(name string, parentID TaskID, timestamp int64) TaskID {
+	idx := tasks.index(func(task Task) bool {
+		return task.Description == name && task.ParentID == parentID
+	})
+	if idx != noIndex {
+		return (*tasks)[idx].UIndex
+	}
+	projectTask := Task{
+		UIndex:      tasks.getFreeUID(),
+		Description: name,
+		Timestamp:   timestamp,
+		Status:      StatusTodo,
+		ParentID:    parentID,
+	}
+	*tasks = append(*tasks, projectTask)
+	return projectTask.UIndex
+}
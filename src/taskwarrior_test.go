@@ -0,0 +1,100 @@
+package todo
+
+import (
+	"testing"
+)
+
+func TestTaskwarriorUUID(t *testing.T) {
+	task := CreateTestTask(1, "A task")
+	uuid := twUUID(task)
+	if len(uuid) != 36 {
+		t.Errorf("The uuid %s is not valid", uuid)
+	}
+	var read Task
+	twSetGlobalID(&read, uuid)
+	if read.GUID != task.GUID {
+		t.Errorf("GUID is %s (should be %s)", read.GUID, task.GUID)
+	}
+
+	// The uuids derived from a GIndex by the former versions
+	gindices := []TaskID{1, 202610191276203404, 0xffffffffffffffff}
+	for _, gindex := range gindices {
+		uuid := twUUID(Task{GIndex: gindex})
+		if len(uuid) != 36 {
+			t.Errorf("The uuid %s is not valid", uuid)
+		}
+		if twGIndex(uuid) != gindex {
+			t.Errorf("GIndex is %d (should be %d)", twGIndex(uuid), gindex)
+		}
+	}
+	if twGIndex("a8b9b4c4-1b0b-4a3d-9d6f-111111111111") != NoUID {
+		t.Error("A foreign uuid should not be read as a GIndex")
+	}
+}
+
+func TestTaskwarriorJSON(t *testing.T) {
+	journal := CreateTestJournal()
+	ptask, _ := journal.GetTask(2)
+	ptask.ParentID = 1
+	ptask.Priority = "B"
+	ptask.OnBoard = true
+	ptask, _ = journal.GetTask(3)
+	ptask.ParentID = 2
+	ptask.NextStatus()
+	ptask.NextStatus()
+
+	text, err := journal.TaskwarriorJSON(TaskFilterAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	printlog(text)
+
+	tasks, _, err := ParseTaskwarriorJSON(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(journal.TaskList) {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), len(journal.TaskList))
+	}
+	for i, task := range tasks {
+		init := journal.TaskList[i]
+		if task.UIndex != init.UIndex || task.ParentID != init.ParentID || task.GUID != init.GUID {
+			t.Errorf("Task %d is not preserved (parent %d, should be %d)", init.UIndex, task.ParentID, init.ParentID)
+		}
+		if task.Description != init.Description || task.Status != init.Status {
+			t.Errorf("Description is %s (should be %s)", task.Description, init.Description)
+		}
+		if task.Priority != init.Priority || task.OnBoard != init.OnBoard {
+			t.Errorf("Priority is %s (should be %s)", task.Priority, init.Priority)
+		}
+	}
+	if tasks[2].CompletionDate() == 0 {
+		t.Error("The completion date should be defined")
+	}
+}
+
+func TestParseTaskwarriorJSON(t *testing.T) {
+	text := `{"id":1,"description":"Paint the fence","entry":"20240101T100000Z","project":"home.garden","status":"pending","uuid":"a8b9b4c4-1b0b-4a3d-9d6f-111111111111","tags":["board"],"depends":"a8b9b4c4-1b0b-4a3d-9d6f-222222222222"},
+{"id":0,"description":"Buy paint","entry":"20240101T100000Z","end":"20240103T100000Z","status":"completed","uuid":"a8b9b4c4-1b0b-4a3d-9d6f-222222222222","annotations":[{"entry":"20240102T100000Z","description":"green"}]},
+{"id":0,"description":"Deleted task","entry":"20240101T100000Z","status":"deleted","uuid":"a8b9b4c4-1b0b-4a3d-9d6f-333333333333"}
+`
+	tasks, notes, err := ParseTaskwarriorJSON(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	printlog(TreeString(tasks))
+	// 2 tasks and the 2 project tasks home and garden
+	if len(tasks) != 4 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 4)
+	}
+	if tasks[1].ParentID != tasks[0].UIndex {
+		t.Errorf("Parent is %d (should be %d)", tasks[1].ParentID, tasks[0].UIndex)
+	}
+	garden, _ := tasks.getTask(tasks[0].ParentID)
+	if garden.Description != "garden" || garden.ParentID == NoUID {
+		t.Errorf("Project is %s (should be %s)", garden.Description, "garden")
+	}
+	if !tasks[0].OnBoard || tasks[1].Status != StatusDone || notes[tasks[1].UIndex] != "green" {
+		t.Errorf("Note is %s (should be %s)", notes[tasks[1].UIndex], "green")
+	}
+}
@@ -28,6 +28,31 @@ func datelabel(timestamp int64) string {
 	return label
 }
 
+// dayStart returns the beginning (midnight) of the day of the date
+func dayStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// weekStart returns the date of the beginning of the week (monday at
+// midnight) that is the given number of weeks before the week of now.
+func weekStart(now int64, weeksBefore int) time.Time {
+	date := time.Unix(now, 0)
+	offset := (int(date.Weekday()) + 6) % 7 // days since monday
+	monday := dayStart(date).AddDate(0, 0, -offset)
+	return monday.AddDate(0, 0, -7*weeksBefore)
+}
+
+// ParseDate returns the timestamp (unix format) of the given date label. The
+// label should be in the ISO format (YYYY-MM-DD) and is interpreted in the
+// local time zone.
+func ParseDate(label string) (int64, error) {
+	date, err := time.ParseInLocation(layoutISO, label, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("ERR: the date %s is not valid (should be YYYY-MM-DD)", label)
+	}
+	return date.Unix(), nil
+}
+
 // hashInt returns an integer hash representation (hash.crc32) of the givent string
 func hashInt(s string) uint32 {
 	b := []byte(s)
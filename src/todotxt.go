@@ -0,0 +1,301 @@
+package todo
+
+// Implementation of the todo.txt format (see https://github.com/todotxt/todo.txt)
+//
+// A task is written on a single line:
+//
+//   x (A) 2026-10-19 2026-10-01 Description +project @context key:value
+//
+// The completion marker "x" is followed by the completion date, and the
+// priority (A) is written as a pri:A extension for the completed tasks. The
+// project is the root ancestor of the task. The attributes of the Task that
+// have no equivalent in todo.txt are written as key:value extensions: id (the
+// UID), parent (the UID of the parent), gid (the GUID), status (for the doing tasks),
+// due, board and note (the absolute path to the note file, whose segments are
+// escaped as in an URL path).
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	todotxtDone   = "x"
+	todotxtLayout = layoutISO
+)
+
+// todotxtPriority matches the priority marker of a todo.txt line
+var todotxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// todotxtPriorityValue matches the value of the extension pri:
+var todotxtPriorityValue = regexp.MustCompile(`^[A-Z]$`)
+
+// projectName returns the todo.txt project name corresponding to the given
+// description (the spaces are not allowed in a project name).
+func projectName(description string) string {
+	return strings.Join(strings.Fields(description), "-")
+}
+
+// rootAncestor returns the root ancestor of the given task (the task itself
+// if it has no parent). The function is protected against parent cycles.
+func (tasks TaskArray) rootAncestor(task Task) Task {
+	visited := map[TaskID]bool{task.UIndex: true}
+	for task.ParentID != NoUID {
+		idx := tasks.indexFromUID(task.ParentID)
+		if idx == noIndex || visited[task.ParentID] {
+			break
+		}
+		task = tasks[idx]
+		visited[task.UIndex] = true
+	}
+	return task
+}
+
+// TodoTxtString returns the todo.txt representation of the given task.
+// The contextName is used as the todo.txt @context of the task.
+func (journal TaskJournal) TodoTxtString(task Task, contextName string) string {
+	fields := make([]string, 0)
+	if task.Status == StatusDone {
+		completion := task.CompletionDate()
+		if completion == 0 {
+			completion = task.Timestamp
+		}
+		fields = append(fields, todotxtDone, time.Unix(completion, 0).Format(todotxtLayout))
+	} else if task.Priority != "" {
+		fields = append(fields, fmt.Sprintf("(%s)", task.Priority))
+	}
+	fields = append(fields, time.Unix(task.Timestamp, 0).Format(todotxtLayout))
+	fields = append(fields, task.Description)
+
+	if root := journal.TaskList.rootAncestor(task); root.UIndex != task.UIndex {
+		fields = append(fields, "+"+projectName(root.Description))
+	}
+	if contextName != "" {
+		fields = append(fields, "@"+projectName(contextName))
+	}
+
+	fields = append(fields, fmt.Sprintf("id:%d", task.UIndex))
+	if task.ParentID != NoUID {
+		fields = append(fields, fmt.Sprintf("parent:%d", task.ParentID))
+	}
+	fields = append(fields, "gid:"+task.globalID())
+	if task.Status == StatusDoing {
+		fields = append(fields, "status:"+task.Status.Label())
+	}
+	if task.Status == StatusDone && task.Priority != "" {
+		fields = append(fields, "pri:"+task.Priority)
+	}
+	if task.Due != 0 {
+		fields = append(fields, "due:"+time.Unix(task.Due, 0).Format(todotxtLayout))
+	}
+	if task.OnBoard {
+		fields = append(fields, "board:yes")
+	}
+	if task.NotePath != "" {
+		fields = append(fields, "note:"+escapeNotePath(journal.absNotePath(task)))
+	}
+	return strings.Join(fields, " ")
+}
+
+// TodoTxt returns the todo.txt representation of the tasks of this journal
+// that satisfy the given filter. The contextName is used as the todo.txt
+// @context of the tasks.
+func (journal TaskJournal) TodoTxt(taskFilter TaskFilter, contextName string) string {
+	s := ""
+	for _, task := range journal.TaskList {
+		if taskFilter(task) {
+			s += journal.TodoTxtString(task, contextName) + "\n"
+		}
+	}
+	return s
+}
+
+// escapeNotePath escapes each segment of the path, so that it can be written
+// as the value of a todo.txt extension (with no space).
+func escapeNotePath(notepath string) string {
+	segments := strings.Split(notepath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// todotxtExtension splits the word into a key:value extension. Returns false
+// if the word is not an extension (e.g. an URL or a time).
+func todotxtExtension(word string) (string, string, bool) {
+	idx := strings.Index(word, ":")
+	if idx <= 0 || idx == len(word)-1 {
+		return "", "", false
+	}
+	key, value := word[:idx], word[idx+1:]
+	if strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) {
+			return "", "", false
+		}
+	}
+	return key, value, true
+}
+
+// todotxtDate parses the word as a todo.txt date
+func todotxtDate(word string) (int64, bool) {
+	date, err := time.ParseInLocation(todotxtLayout, word, time.Local)
+	if err != nil {
+		return 0, false
+	}
+	return date.Unix(), true
+}
+
+// ParseTodoTxt parses the todo.txt text and returns the list of tasks. The
+// UIndex and ParentID of the tasks are the id and parent extensions, to be
+// remapped with TaskJournal.Import. A task with a +project and no parent
+// becomes the child of the task whose description is the project name (the
+// project task is created if it does not exist). The @context markers are
+// ignored, the tasks are imported in the journal.
+func ParseTodoTxt(text string) (TaskArray, error) {
+	tasks := make(TaskArray, 0)
+	projects := make(map[int]string) // project of the task (by index in tasks)
+	uids := make(map[TaskID]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	nline := 0
+	for scanner.Scan() {
+		nline++
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+		task := Task{Status: StatusTodo, Timestamp: timestamp()}
+
+		if words[0] == todotxtDone {
+			task.Status = StatusDone
+			words = words[1:]
+		} else if match := todotxtPriority.FindStringSubmatch(words[0]); match != nil {
+			task.Priority = match[1]
+			words = words[1:]
+		}
+		dates := make([]int64, 0, 2)
+		for len(words) > 0 && len(dates) < 2 {
+			date, ok := todotxtDate(words[0])
+			if !ok {
+				break
+			}
+			dates = append(dates, date)
+			words = words[1:]
+		}
+		if len(dates) == 2 || (len(dates) == 1 && task.Status != StatusDone) {
+			task.Timestamp = dates[len(dates)-1]
+		}
+		if task.Status == StatusDone && len(dates) > 0 {
+			task.History = StatusHistory{{Status: StatusDone, Timestamp: dates[0]}}
+		}
+
+		description := make([]string, 0, len(words))
+		for _, word := range words {
+			if strings.HasPrefix(word, "+") && len(word) > 1 {
+				projects[len(tasks)] = word[1:]
+				continue
+			}
+			if strings.HasPrefix(word, "@") && len(word) > 1 {
+				continue
+			}
+			key, value, ok := todotxtExtension(word)
+			if !ok {
+				description = append(description, word)
+				continue
+			}
+			var err error
+			switch key {
+			case "id":
+				task.UIndex, err = parseUID(value)
+			case "parent":
+				task.ParentID, err = parseUID(value)
+			case "gid":
+				if !task.setGlobalID(value) {
+					err = fmt.Errorf("ERR: %s is not a valid global identifier", value)
+				}
+			case "status":
+				err = task.Status.Value(value)
+			case "pri":
+				if !todotxtPriorityValue.MatchString(value) {
+					err = fmt.Errorf("ERR: %s is not a priority (A to Z)", value)
+				}
+				task.Priority = value
+			case "due":
+				task.Due, err = ParseDate(value)
+			case "board":
+				task.OnBoard = value == "yes"
+			case "note":
+				var notepath string
+				notepath, err = url.PathUnescape(value)
+				if err == nil {
+					if exists, _ := PathExists(notepath); !exists {
+						err = fmt.Errorf("ERR: the note file %s does not exist", notepath)
+					}
+				}
+				task.NotePath = notepath
+			default:
+				description = append(description, word)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("ERR: line %d: the extension %s is not valid (%s)", nline, word, err)
+			}
+		}
+		task.Description = strings.Join(description, " ")
+		if task.UIndex != NoUID {
+			if uids[task.UIndex] {
+				return nil, fmt.Errorf("ERR: line %d: the id %d is already used", nline, task.UIndex)
+			}
+			uids[task.UIndex] = true
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The tasks with no id get an id that is not used in the file
+	nextUID := func() TaskID {
+		var uid TaskID = 1
+		for uids[uid] {
+			uid++
+		}
+		uids[uid] = true
+		return uid
+	}
+	for i := 0; i < len(tasks); i++ {
+		if tasks[i].UIndex == NoUID {
+			tasks[i].UIndex = nextUID()
+		}
+	}
+
+	// The project markers are resolved into parent relations
+	for i := 0; i < len(tasks); i++ {
+		project, exists := projects[i]
+		if !exists || tasks[i].ParentID != NoUID {
+			continue
+		}
+		idx := tasks.index(func(task Task) bool { return projectName(task.Description) == project })
+		if idx == noIndex {
+			projectTask := Task{
+				UIndex:      nextUID(),
+				Description: strings.ReplaceAll(project, "-", " "),
+				Timestamp:   tasks[i].Timestamp,
+				Status:      StatusTodo,
+			}
+			tasks = append(tasks, projectTask)
+			idx = len(tasks) - 1
+		}
+		if idx != i {
+			tasks[i].ParentID = tasks[idx].UIndex
+		}
+	}
+	return tasks, nil
+}
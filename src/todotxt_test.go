@@ -0,0 +1,114 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTodoTxt(t *testing.T) {
+	journal := CreateTestJournal()
+	ptask, _ := journal.GetTask(2)
+	ptask.ParentID = 1
+	ptask.Priority = "A"
+	ptask.OnBoard = true
+	ptask, _ = journal.GetTask(3)
+	ptask.ParentID = 2
+	ptask.NextStatus()
+	ptask.NextStatus()
+
+	text := journal.TodoTxt(TaskFilterAll, "work")
+	printlog(text)
+
+	tasks, err := ParseTodoTxt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(journal.TaskList) {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), len(journal.TaskList))
+	}
+	for i, task := range tasks {
+		init := journal.TaskList[i]
+		if task.UIndex != init.UIndex || task.ParentID != init.ParentID || task.GUID != init.GUID {
+			t.Errorf("Task %d is not preserved (parent %d, should be %d)", init.UIndex, task.ParentID, init.ParentID)
+		}
+		if task.Description != init.Description || task.Status != init.Status {
+			t.Errorf("Description is %s (should be %s)", task.Description, init.Description)
+		}
+		if task.Priority != init.Priority || task.OnBoard != init.OnBoard {
+			t.Errorf("Priority is %s (should be %s)", task.Priority, init.Priority)
+		}
+	}
+	if tasks[2].CompletionDate() == 0 {
+		t.Error("The completion date should be defined")
+	}
+}
+
+func TestTodoTxtNotePath(t *testing.T) {
+	journal := CreateTestJournal()
+	journal.filepath = filepath.Join(t.TempDir(), "50% done", "my tasks", JournalFilename)
+	notepath, err := journal.GetOrCreateNoteFile(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := ParseTodoTxt(journal.TodoTxt(TaskFilterAll, "work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks[0].NotePath != notepath {
+		t.Errorf("The note path is %s (should be %s)", tasks[0].NotePath, notepath)
+	}
+}
+
+func TestParseTodoTxt(t *testing.T) {
+	text := `(B) 2024-01-05 Call the plumber +house @home
+x 2024-01-10 2024-01-02 Buy paint +house
+Read https://example.com at 10:30 status:doing
+`
+	tasks, err := ParseTodoTxt(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(tasks), 4)
+	}
+	if tasks[0].Priority != "B" || tasks[0].Description != "Call the plumber" {
+		t.Errorf("Description is %s (should be %s)", tasks[0].Description, "Call the plumber")
+	}
+	if datelabel(tasks[1].Timestamp) != "2024-Jan-02" || datelabel(tasks[1].CompletionDate()) != "2024-Jan-10" {
+		t.Errorf("Creation date is %s (should be %s)", datelabel(tasks[1].Timestamp), "2024-Jan-02")
+	}
+	if tasks[2].Status != StatusDoing || tasks[2].Description != "Read https://example.com at 10:30" {
+		t.Errorf("Description is %s (should be %s)", tasks[2].Description, "Read https://example.com at 10:30")
+	}
+	project := tasks[3]
+	if project.Description != "house" || tasks[0].ParentID != project.UIndex || tasks[1].ParentID != project.UIndex {
+		t.Errorf("The project task is %s (should be %s)", project.Description, "house")
+	}
+}
+
+func TestParseTodoTxtExtensions(t *testing.T) {
+	tasks, err := ParseTodoTxt("x 2024-01-10 Paint the door id:3 parent:2 pri:C\nBuy paint id:2\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks[0].UIndex != 3 || tasks[0].ParentID != 2 || tasks[0].Priority != "C" {
+		t.Errorf("The task is %d (parent %d, priority %s) (should be %d (parent %d, priority %s))",
+			tasks[0].UIndex, tasks[0].ParentID, tasks[0].Priority, 3, 2, "C")
+	}
+
+	invalid := []string{
+		"Paint the door id:t1",
+		"Paint the door parent:t1",
+		"Paint the door id:9223372036854775808",
+		"Paint the door id:three",
+		"Paint the door pri:high",
+		"Paint the door pri:c",
+		"Paint the door note:/nonexistent/door.rst",
+	}
+	for _, text := range invalid {
+		if _, err := ParseTodoTxt(text); err == nil {
+			t.Errorf("The line %q is not valid and should raise an error", text)
+		}
+	}
+}
@@ -22,23 +22,85 @@ func (tree *treeMap) addChild(parentID TaskID, childID TaskID) error {
 	return nil
 }
 
-// initialize initializes the tree structure from the data array
-func (tree *treeMap) initialize(tasks TaskArray) error {
+// initialize initializes the tree structure from the data array. The tasks
+// that are parent of themselves (a cycle left by a former version of todo) are
+// not added as children, so that they are handled as unreachable tasks.
+func (tree *treeMap) initialize(tasks TaskArray) {
 	for i := 0; i < len(tasks); i++ {
 		task := tasks[i]
-		err := tree.addChild(TaskID(task.ParentID), TaskID(task.UIndex))
-		if err != nil {
-			return err
+		if task.ParentID == task.UIndex {
+			continue
 		}
+		tree.addChild(TaskID(task.ParentID), TaskID(task.UIndex))
 	}
-	return nil
 }
 
+// descendants returns the IDs of all the descendants of the data of ID taskID
+// (children, grandchildren, and so on). The IDs are listed in depth-first order.
+func (tree treeMap) descendants(taskID TaskID) TaskIDArray {
+	results := make(TaskIDArray, 0)
+	visited := map[TaskID]bool{taskID: true}
+	var walk func(parentID TaskID)
+	walk = func(parentID TaskID) {
+		for _, childID := range tree[parentID] {
+			if visited[childID] {
+				// Protection against the cycles of parent relations
+				continue
+			}
+			visited[childID] = true
+			results = append(results, childID)
+			walk(childID)
+		}
+	}
+	walk(taskID)
+	return results
+}
+
+// TreeOptions are the options of the tree representation of the tasks
+type TreeOptions struct {
+	Root         TaskID     // Print only the subtree of this task (all the trees if NoUID)
+	Depth        int        // Number of levels of the tree that are printed (all if 0)
+	CollapseDone bool       // Fold the branches whose tasks are all done in one line
+	Filter       TaskFilter // Print only these tasks and their ancestors (all if nil)
+}
+
+// collapsedMark is appended to the line of a folded branch
+const collapsedMark = " (folded)"
+
 // TreeString returns a tree representation of the dataArray
 func TreeString(tasks TaskArray) string {
+	return treeString(tasks, Task.String, TreeOptions{})
+}
+
+// treeString returns a tree representation of the dataArray where each task is
+// represented with the taskString function. The parent tasks are followed by
+// their progress, e.g. [3/5] 60%.
+func treeString(tasks TaskArray, taskString func(Task) string, options TreeOptions) string {
 	// Create the children tree
 	tree := make(treeMap, 0)
 	tree.initialize(tasks)
+	progresses := tasks.progress()
+
+	// The visible tasks are the tasks that satisfy the filter and all their
+	// ancestors
+	visible := func(taskID TaskID) bool { return true }
+	if options.Filter != nil {
+		shown := make(map[TaskID]bool)
+		for _, task := range tasks {
+			if !options.Filter(task) {
+				continue
+			}
+			for uindex := task.UIndex; uindex != NoUID && !shown[uindex]; {
+				shown[uindex] = true
+				parent, err := tasks.getTask(uindex)
+				if err != nil {
+					break
+				}
+				uindex = parent.ParentID
+			}
+		}
+		visible = func(taskID TaskID) bool { return shown[taskID] }
+	}
 
 	// The principle is to iterate on dataArray root elements (element with no
 	// parent) and then create a string reresentation of the children tree using
@@ -57,11 +119,28 @@ func TreeString(tasks TaskArray) string {
 		return s
 	}(len(tabstart))
 
-	// nodeString is the recursive function
-	var nodeString func(taskID TaskID, tab string) string
-	nodeString = func(taskID TaskID, tab string) string {
+	// nodeString is the recursive function. The visited tasks are not
+	// visited again, as a protection against the cycles of parent relations
+	visited := make(map[TaskID]bool)
+	var nodeString func(taskID TaskID, tab string, depth int) string
+	nodeString = func(taskID TaskID, tab string, depth int) string {
+		if visited[taskID] || !visible(taskID) {
+			return ""
+		}
+		visited[taskID] = true
 		idx := tasks.indexFromUID(taskID)
-		s := fmt.Sprintf("%s%s\n", tab, tasks[idx].String())
+		task := tasks[idx]
+		task.progress = progresses[taskID]
+		line := taskString(task)
+		if task.progress.Total > 0 {
+			line += fmt.Sprintf(" %s %d%%", task.progress, task.progress.Percent())
+		}
+		collapsed := options.CollapseDone && task.progress.Total > 0 &&
+			task.Status == StatusDone && task.progress.Done == task.progress.Total
+		if collapsed {
+			line += collapsedMark
+		}
+		s := fmt.Sprintf("%s%s\n", tab, line)
 
 		// If the task is a main task (i.e. a task with no parent, which
 		// can be determine by testing the current tabulation), then we
@@ -70,7 +149,10 @@ func TreeString(tasks TaskArray) string {
 			s = groupsep + s
 		}
 
-		// Is there children tasks?
+		// Is there children tasks (that are printed)?
+		if collapsed || options.Depth > 0 && depth+1 >= options.Depth {
+			return s
+		}
 		_, exists := tree[taskID]
 		if !exists {
 			// This task has no child => stop the recurcive loop
@@ -91,15 +173,79 @@ func TreeString(tasks TaskArray) string {
 		}
 
 		for i := 0; i < len(children); i++ {
-			s += nodeString(children[i], tab)
+			s += nodeString(children[i], tab, depth+1)
 		}
 		return s
 	}
 
+	if options.Root != NoUID {
+		return nodeString(options.Root, tabstart, 0)
+	}
+
 	stree := ""
 	noParentTaskIDs := tree[NoUID]
+	reached := make(map[TaskID]bool)
 	for k := 0; k < len(noParentTaskIDs); k++ {
-		stree += nodeString(noParentTaskIDs[k], tabstart)
+		stree += nodeString(noParentTaskIDs[k], tabstart, 0)
+		reached[noParentTaskIDs[k]] = true
+		for _, uindex := range tree.descendants(noParentTaskIDs[k]) {
+			reached[uindex] = true
+		}
+	}
+	// The tasks that can not be reached from a root task (the tasks whose
+	// parent does not exist, and the cycles of parent relations) are printed
+	// as root tasks, so that no task is missing in the tree
+	for _, task := range tasks {
+		if reached[task.UIndex] {
+			continue
+		}
+		stree += nodeString(task.UIndex, tabstart, 0)
+		reached[task.UIndex] = true
+		for _, uindex := range tree.descendants(task.UIndex) {
+			reached[uindex] = true
+		}
 	}
 	return stree
 }
+
+// walkTree calls the visit function on the tasks that satisfy the filter, in
+// the depth-first order of the tree structure, with the depth of the task in
+// the tree (0 for a root task). A task whose parent does not satisfy the filter
+// (or does not exist) is considered as a root task. The tasks that are not
+// reachable from a root task (cycles in the parent relations) are visited at
+// the end, as root tasks.
+func walkTree(tasks TaskArray, filter TaskFilter, visit func(task Task, depth int)) {
+	selected := make(TaskArray, 0, len(tasks))
+	for _, task := range tasks {
+		if filter(task) {
+			selected = append(selected, task)
+		}
+	}
+	tree := make(treeMap, 0)
+	for _, task := range selected {
+		parentID := task.ParentID
+		if selected.indexFromUID(parentID) == noIndex {
+			parentID = NoUID
+		}
+		tree.addChild(parentID, task.UIndex)
+	}
+
+	visited := make(map[TaskID]bool)
+	var walk func(taskID TaskID, depth int)
+	walk = func(taskID TaskID, depth int) {
+		if visited[taskID] {
+			return
+		}
+		visited[taskID] = true
+		visit(selected[selected.indexFromUID(taskID)], depth)
+		for _, childID := range tree[taskID] {
+			walk(childID, depth+1)
+		}
+	}
+	for _, taskID := range tree[NoUID] {
+		walk(taskID, 0)
+	}
+	for _, task := range selected {
+		walk(task.UIndex, 0)
+	}
+}
@@ -2,6 +2,7 @@ package todo
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -48,6 +49,39 @@ func createTreeTaskArray() TaskArray {
 	return tasks
 }
 
+// checkTreeRoundTrip checks that the tasks parsed from an export of the tasks
+// of createTreeTaskArray have the same descriptions and parent relations. It
+// returns a function to get a parsed task from its description.
+func checkTreeRoundTrip(t *testing.T, parsed TaskArray) func(description string) Task {
+	t.Helper()
+	tasks := createTreeTaskArray()
+	if len(parsed) != len(tasks) {
+		t.Fatalf("Nb tasks is %d (should be %d)", len(parsed), len(tasks))
+	}
+	indexFromText := func(tasks TaskArray, description string) int {
+		return tasks.index(func(task Task) bool { return task.Description == description })
+	}
+	parentText := func(tasks TaskArray, task Task) string {
+		if idx := tasks.indexFromUID(task.ParentID); idx != noIndex {
+			return tasks[idx].Description
+		}
+		return ""
+	}
+	for _, task := range tasks {
+		idx := indexFromText(parsed, task.Description)
+		if idx == noIndex {
+			t.Fatalf("The task %s is missing", task.Description)
+		}
+		parent, expected := parentText(parsed, parsed[idx]), parentText(tasks, task)
+		if parent != expected {
+			t.Errorf("The parent of %s is %q (should be %q)", task.Description, parent, expected)
+		}
+	}
+	return func(description string) Task {
+		return parsed[indexFromText(parsed, description)]
+	}
+}
+
 func TestTaskArrayAncestor(t *testing.T) {
 	viewlog = false
 
@@ -124,7 +158,19 @@ func TestTaskArrayTreeString(t *testing.T) {
 	printlog("====================================")
 	printlog("The resulting tree string is:")
 	printlog("====================================")
-	printlog(TreeString(tasks))
+	tree := TreeString(tasks)
+	printlog(tree)
+	if strings.Count(tree, " : ") != len(tasks) {
+		t.Errorf("Nb tasks in the tree is %d (should be %d)", strings.Count(tree, " : "), len(tasks))
+	}
+	for _, task := range tasks {
+		if !strings.Contains(tree, " : "+task.Description) {
+			t.Errorf("The task %s should be printed in the tree", task.Description)
+		}
+	}
+	if tasks.ancestor(taskFromText("B.2.1").UIndex, taskFromText("B").UIndex) {
+		t.Errorf("B should not be an ancestor of B.2.1 anymore")
+	}
 
 	// Restore the dependency
 	taskFromText("B.2").ParentID = taskFromText("B").UIndex
@@ -149,3 +195,150 @@ func TestTaskArrayTreeString(t *testing.T) {
 	viewlog = false
 
 }
+
+func TestTaskArrayTreeStringSelfParent(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	expected := treeString(journal.TaskList, Task.String, TreeOptions{Root: 31})
+
+	// Simulate the cycles A -> A (the first task) and D -> D
+	ptask, _ := journal.GetTask(30)
+	ptask.ParentID = 30
+	ptask, _ = journal.GetTask(14)
+	ptask.ParentID = 14
+
+	tree := TreeString(journal.TaskList)
+	printlog(tree)
+	if strings.Count(tree, " : ") != len(journal.TaskList) {
+		t.Errorf("Nb tasks in the tree is %d (should be %d)", strings.Count(tree, " : "), len(journal.TaskList))
+	}
+	subtree := treeString(journal.TaskList, Task.String, TreeOptions{Root: 31})
+	if subtree != expected {
+		t.Errorf("The tree of B is:\n%s\n(should be:\n%s)", subtree, expected)
+	}
+
+	uids, err := journal.WithDescendants(TaskIDArray{8})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(uids) != 4 {
+		t.Errorf("The UIDs are %v (should be %v)", uids, TaskIDArray{8, 11, 12, 13})
+	}
+	uids, _ = journal.WithDescendants(TaskIDArray{30})
+	if len(uids) != 4 {
+		t.Errorf("The UIDs are %v (should be %v)", uids, TaskIDArray{30, 4, 5, 6})
+	}
+}
+
+func TestTaskJournalWithDescendants(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+
+	uids, err := journal.WithDescendants(TaskIDArray{8, 31})
+	if err != nil {
+		t.Error(err)
+	}
+	expected := map[TaskID]bool{31: true, 7: true, 8: true, 11: true, 12: true, 13: true}
+	if len(uids) != len(expected) {
+		t.Errorf("The UIDs are %v (should be %d UIDs)", uids, len(expected))
+	}
+	position := make(map[TaskID]int)
+	for i, uid := range uids {
+		if !expected[uid] {
+			t.Errorf("The UID %d should not be selected", uid)
+		}
+		position[uid] = i
+	}
+	for _, task := range journal.TaskList {
+		if expected[task.UIndex] && expected[task.ParentID] && position[task.ParentID] > position[task.UIndex] {
+			t.Errorf("The task %d should be listed after its parent %d", task.UIndex, task.ParentID)
+		}
+	}
+
+	_, err = journal.WithDescendants(TaskIDArray{99})
+	if err == nil {
+		t.Errorf("The task 99 does not exist and should raise an error")
+	}
+}
+
+func TestTaskJournalCascadeStatus(t *testing.T) {
+	journal := TaskJournal{TaskList: createTreeTaskArray()}
+	check := func(expected map[TaskID]TaskStatus) {
+		t.Helper()
+		for uindex, status := range expected {
+			task, _ := journal.GetTask(uindex)
+			if task.Status != status {
+				t.Errorf("The status of %d is %s (should be %s)", uindex, task.Status.Label(), status.Label())
+			}
+		}
+	}
+	// B.2.1 (11) is done, B.2.2 (12) is doing, B.2.2.1 (13) is todo
+	ptask, _ := journal.GetTask(11)
+	ptask.SetStatus(StatusDone)
+	ptask, _ = journal.GetTask(12)
+	ptask.SetStatus(StatusDoing)
+
+	// B.2 (8) from todo to doing: only the todo descendants move to doing
+	ptask, _ = journal.GetTask(8)
+	ptask.NextStatus()
+	changed, err := journal.CascadeStatus(8, (*Task).NextStatus)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(changed) != 1 || changed[0] != 13 {
+		t.Errorf("The changed tasks are %v (should be %v)", changed, TaskIDArray{13})
+	}
+	check(map[TaskID]TaskStatus{8: StatusDoing, 11: StatusDone, 12: StatusDoing, 13: StatusDoing})
+
+	// B.2 (8) from doing to todo: each descendant moves one step back
+	ptask.PreviousStatus()
+	changed, _ = journal.CascadeStatus(8, (*Task).PreviousStatus)
+	if len(changed) != 3 {
+		t.Errorf("The changed tasks are %v (should be %v)", changed, TaskIDArray{11, 12, 13})
+	}
+	check(map[TaskID]TaskStatus{8: StatusTodo, 11: StatusDoing, 12: StatusTodo, 13: StatusTodo})
+
+	_, err = journal.CascadeStatus(99, (*Task).NextStatus)
+	if err == nil {
+		t.Errorf("The task 99 does not exist and should raise an error")
+	}
+}
+
+func TestTaskArrayTreeOptions(t *testing.T) {
+	tasks := createTreeTaskArray()
+	uindeces := func(tree string) TaskIDArray {
+		uids := make(TaskIDArray, 0)
+		for _, task := range tasks {
+			if strings.Contains(tree, " : "+task.Description+"\n") || strings.Contains(tree, " : "+task.Description+" [") {
+				uids = append(uids, task.UIndex)
+			}
+		}
+		return uids
+	}
+	check := func(name string, options TreeOptions, expected int) string {
+		tree := treeString(tasks, Task.String, options)
+		printlog(tree)
+		if strings.Count(tree, " : ") != expected {
+			t.Errorf("%s: Nb tasks is %d (should be %d): %v", name, strings.Count(tree, " : "), expected, uindeces(tree))
+		}
+		return tree
+	}
+
+	// Subtree of B.2 (8): 8, 11, 12, 13
+	check("root", TreeOptions{Root: 8}, 4)
+	// Two levels: the 4 roots and their 7 children
+	check("depth", TreeOptions{Depth: 2}, 11)
+	check("root and depth", TreeOptions{Root: 8, Depth: 2}, 3)
+
+	// The branch B.2.2 (12, 13) is done and folded
+	for _, uindex := range []TaskID{12, 13} {
+		tasks[tasks.indexFromUID(uindex)].Status = StatusDone
+	}
+	tree := check("collapse", TreeOptions{CollapseDone: true}, len(tasks)-1)
+	if strings.Count(tree, collapsedMark) != 1 {
+		t.Errorf("Nb folded branches is %d (should be %d)", strings.Count(tree, collapsedMark), 1)
+	}
+
+	// The done task B.2.2.1 (13) is printed with its ancestors 31, 8, 12
+	filter := func(task Task) bool { return task.Description == "B.2.2.1" }
+	check("filter", TreeOptions{Filter: filter}, 4)
+	check("filter none", TreeOptions{Filter: func(task Task) bool { return false }}, 0)
+}
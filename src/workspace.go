@@ -0,0 +1,135 @@
+package todo
+
+// Implementation of the per-directory discovery of the active context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// WorkspaceMarker is the name of the workspace marker searched in the
+	// current directory and its parents. The marker could be a directory (a
+	// local context workspace containing the journal, the archive and the
+	// notes) or a file (containing the name of a context of the configuration).
+	WorkspaceMarker = ".todo"
+
+	// ContextEnvVariable is the environment variable that overrides the active
+	// context for a single invocation (value is the name of a context)
+	ContextEnvVariable = "TODO_CONTEXT"
+)
+
+// FindWorkspace searches for a workspace marker in the directory dirpath and
+// its parents (like git finds the .git folder). Returns the absolute path of
+// the first marker found, or a blank string ("") if there is no marker.
+func FindWorkspace(dirpath string) (string, error) {
+	dirpath, err := filepath.Abs(dirpath)
+	if err != nil {
+		return "", err
+	}
+	for {
+		markerpath := filepath.Join(dirpath, WorkspaceMarker)
+		exists, err := PathExists(markerpath)
+		if exists && err != nil {
+			return "", err
+		}
+		if exists {
+			return markerpath, nil
+		}
+		parent := filepath.Dir(dirpath)
+		if parent == dirpath {
+			return "", nil
+		}
+		dirpath = parent
+	}
+}
+
+// InitWorkspace creates a workspace marker in the directory dirpath. If
+// contextName is blank, then the marker is a local context workspace (a
+// directory with a void journal), otherwise the marker is a file that refers to
+// the context of this name. Returns the path of the marker.
+func InitWorkspace(dirpath string, contextName string) (string, error) {
+	markerpath := filepath.Join(dirpath, WorkspaceMarker)
+	exists, err := PathExists(markerpath)
+	if exists {
+		if err != nil {
+			return markerpath, err
+		}
+		return markerpath, fmt.Errorf("ERR: the workspace %s already exists", markerpath)
+	}
+
+	if contextName != "" {
+		return markerpath, WriteBytes(markerpath, []byte(contextName+"\n"))
+	}
+	journal := TaskJournal{TaskList: make(TaskArray, 0)}
+	return markerpath, journal.SaveTo(filepath.Join(markerpath, JournalFilename))
+}
+
+// workspaceContext returns the context defined by the workspace marker
+// markerpath (see WorkspaceMarker).
+func (config *Config) workspaceContext(markerpath string) (*Context, error) {
+	info, err := os.Stat(markerpath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		context := Context{
+			DirPath: markerpath,
+			Name:    filepath.Base(filepath.Dir(markerpath)),
+		}
+		return &context, nil
+	}
+	bytes, err := LoadBytes(markerpath)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(string(bytes))
+	if name == "" {
+		return nil, fmt.Errorf("ERR: the workspace marker %s does not specify a context", markerpath)
+	}
+	context := config.GetContext(name)
+	if context == nil {
+		return nil, fmt.Errorf("ERR: The context %s (specified in %s) does not exists", name, markerpath)
+	}
+	return context, nil
+}
+
+// discoverActiveContext determines if the active context of the configuration
+// is overridden for this invocation, either by the environment variable
+// ContextEnvVariable or by a workspace marker found from the directory dirpath.
+// The environment variable has precedence over the workspace marker.
+func (config *Config) discoverActiveContext(dirpath string) error {
+	if name := os.Getenv(ContextEnvVariable); name != "" {
+		context := config.GetContext(name)
+		if context == nil {
+			return fmt.Errorf("ERR: The context %s (specified by %s) does not exists", name, ContextEnvVariable)
+		}
+		config.activeContext = context
+		config.activeOrigin = fmt.Sprintf("environment variable %s", ContextEnvVariable)
+		return nil
+	}
+
+	markerpath, err := FindWorkspace(dirpath)
+	if err != nil || markerpath == "" {
+		return err
+	}
+	context, err := config.workspaceContext(markerpath)
+	if err != nil {
+		return err
+	}
+	config.activeContext = context
+	config.activeOrigin = fmt.Sprintf("workspace %s", markerpath)
+	return nil
+}
+
+// ActiveContextOrigin returns a description of what overrides the active
+// context of the configuration, or a blank string ("") if it is not
+// overridden.
+func (config *Config) ActiveContextOrigin() string {
+	if config.activeContext == nil {
+		return ""
+	}
+	return config.activeOrigin
+}
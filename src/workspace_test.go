@@ -0,0 +1,102 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	rootdir := t.TempDir()
+	subdir := filepath.Join(rootdir, "project", "src")
+	os.MkdirAll(subdir, os.ModePerm)
+
+	markerpath, err := FindWorkspace(subdir)
+	if err != nil || markerpath != "" {
+		t.Errorf("No workspace should be found from %s (found %s)", subdir, markerpath)
+	}
+
+	markerpath, err = InitWorkspace(filepath.Join(rootdir, "project"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = InitWorkspace(filepath.Join(rootdir, "project"), "")
+	if err == nil {
+		t.Error("The workspace already exists")
+	}
+
+	found, err := FindWorkspace(subdir)
+	if err != nil || found != markerpath {
+		t.Errorf("workspace is %s (should be %s)", found, markerpath)
+	}
+
+	config := CreateTestConfig()
+	t.Setenv(ContextEnvVariable, "")
+	err = config.discoverActiveContext(subdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	context := config.GetActiveContext()
+	if context.Name != "project" || context.DirPath != markerpath {
+		t.Errorf("active context is %s (should be %s)", context.Name, "project")
+	}
+
+	t.Setenv(ContextEnvVariable, "tutu")
+	err = config.discoverActiveContext(subdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.GetActiveContext().Name != "tutu" {
+		t.Errorf("active context is %s (should be %s)", config.GetActiveContext().Name, "tutu")
+	}
+
+	linkdir := filepath.Join(rootdir, "other")
+	_, err = InitWorkspace(linkdir, "titi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ContextEnvVariable, "")
+	config = CreateTestConfig()
+	config.discoverActiveContext(linkdir)
+	if config.GetActiveContext().Name != "titi" {
+		t.Errorf("active context is %s (should be %s)", config.GetActiveContext().Name, "titi")
+	}
+}
+
+func TestIgnoreInvalidContextOverride(t *testing.T) {
+	defer SetConfigRoot(cfgrootdir)
+	defer IgnoreInvalidContextOverride(false)
+
+	SetConfigRoot(t.TempDir())
+	t.Setenv(ContextEnvVariable, "nope")
+	if _, err := GetConfig(); err == nil {
+		t.Error("The context nope does not exist and should raise an error")
+	}
+
+	IgnoreInvalidContextOverride(true)
+	config, err := GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ActiveContextOrigin() != "" {
+		t.Errorf("The active context should not be overridden (by %s)", config.ActiveContextOrigin())
+	}
+	if !strings.Contains(config.ContextsString(), "is ignored: The context nope") {
+		t.Errorf("The invalid override should be reported:%s", config.ContextsString())
+	}
+
+	// A valid override is still applied
+	t.Setenv(ContextEnvVariable, defaultContextName)
+	IgnoreInvalidContextOverride(true)
+	config, err = GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ActiveContextOrigin() == "" {
+		t.Error("The active context should be overridden")
+	}
+	if !strings.Contains(config.ContextsString(), "is overridden by the environment variable") {
+		t.Errorf("The override should be reported:%s", config.ContextsString())
+	}
+}